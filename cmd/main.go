@@ -2,78 +2,379 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"syscall"
+	"time"
 
 	"github.com/MaximVod/steambotgo/internal/adapters"
 	"github.com/MaximVod/steambotgo/internal/config"
-	// "github.com/MaximVod/steambotgo/internal/database" // Временно отключено
+	"github.com/MaximVod/steambotgo/internal/database"
 	"github.com/MaximVod/steambotgo/internal/handlers"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
 	"github.com/MaximVod/steambotgo/internal/logger"
+	"github.com/MaximVod/steambotgo/internal/metrics"
 	"github.com/MaximVod/steambotgo/internal/presenters"
+	"github.com/MaximVod/steambotgo/internal/ratelimit"
+	"github.com/MaximVod/steambotgo/internal/scheduler"
+	"github.com/MaximVod/steambotgo/internal/server"
+	"github.com/MaximVod/steambotgo/internal/usecases"
+	"github.com/MaximVod/steambotgo/migrations"
 	"github.com/go-telegram/bot"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 )
 
+// telegramPollTimeout — таймаут long polling и HTTP клиента Telegram (как в библиотеке по умолчанию)
+const telegramPollTimeout = time.Minute
+
 func main() {
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	// docker stop отправляет SIGTERM, Ctrl+C — SIGINT
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
+	// ./main migrate up|down [N]|status — управление схемой без запуска бота
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("Ошибка миграции: %v", err)
+		}
+		return
+	}
+
 	cfg, err := loadConfig()
 	if err != nil {
 		log.Fatalf("Ошибка загрузки конфигурации: %v", err)
 	}
 
 	// Инициализируем логгер
-	appLogger := logger.New()
-	appLogger.Info("Запуск приложения")
-
-	// Временно отключено подключение к базе данных
-	// appLogger.Info("Подключение к базе данных", "url", cfg.Database.URL)
-	// dbPool, err := database.InitDB(ctx, cfg.Database.URL)
-	// if err != nil {
-	// 	appLogger.Error("Ошибка подключения к БД", err)
-	// 	log.Fatalf("Не удалось подключиться к базе данных: %v", err)
-	// }
-	// defer database.Close(dbPool) // Закрываем соединение при завершении приложения
-	// appLogger.Info("Успешно подключено к базе данных")
-
-	// Временно отключено применение миграций
-	// appLogger.Info("Применение миграций")
-	// if err := database.RunMigrations(ctx, dbPool); err != nil {
-	// 	appLogger.Error("Ошибка применения миграций", err)
-	// 	log.Fatalf("Не удалось применить миграции: %v", err)
-	// }
-	// appLogger.Info("Миграции применены успешно")
+	appLogger, err := logger.New(cfg.Log.Format, cfg.Log.Level)
+	if err != nil {
+		log.Fatalf("Некорректная конфигурация: %v", err)
+	}
+	appLogger.Info(ctx, "Запуск приложения")
+
+	appLogger.Info(ctx, "Подключение к базе данных")
+	dbPool, err := database.InitDB(ctx, cfg.Database.URL)
+	if err != nil {
+		appLogger.Error(ctx, "Ошибка подключения к БД", err)
+		log.Fatalf("Не удалось подключиться к базе данных: %v", err)
+	}
+	defer database.Close(dbPool) // Закрываем соединение при завершении приложения
+	appLogger.Info(ctx, "Успешно подключено к базе данных")
+
+	appLogger.Info(ctx, "Применение миграций")
+	migrator, err := database.NewMigrator(dbPool, migrations.FS)
+	if err != nil {
+		log.Fatalf("Не удалось загрузить миграции: %v", err)
+	}
+	applied, err := migrator.Up(ctx)
+	for _, migration := range applied {
+		appLogger.Info(ctx, "Применена миграция", "migration", migration.String())
+	}
+	if err != nil {
+		appLogger.Error(ctx, "Ошибка применения миграций", err)
+		log.Fatalf("Не удалось применить миграции: %v", err)
+	}
+	appLogger.Info(ctx, "Миграции применены успешно", "applied", len(applied))
+
+	// Метрики собираются, только если включен их HTTP сервер; nil отключает сбор
+	var appMetrics *metrics.Metrics
+	if cfg.Metrics.Enabled {
+		appMetrics = metrics.New(adapters.SystemClock{})
+	}
 
 	// Инициализируем компоненты
-	steamAPI := adapters.NewSteamGamesAPI(cfg.Steam.BaseURL, cfg.Steam.Timeout)
-	aiAPI := adapters.AiQueriesAPI{}
-	formatter := presenters.NewMessageFormatter()
-	telegramHandler := handlers.NewTelegramHandler(
+	steamAPI := adapters.NewSteamGamesAPI(cfg.Steam.BaseURL, cfg.Steam.Timeout, cfg.Steam.MaxRetries, appMetrics)
+	cacheStore, err := newCacheStore(cfg.Cache, dbPool)
+	if err != nil {
+		log.Fatalf("Некорректная конфигурация: %v", err)
+	}
+	// Команды пользователей идут через кэш, а фоновая проверка цен — напрямую в Steam,
+	// чтобы уведомления строились по свежим ценам
+	cachedSteamAPI := adapters.NewCachedSteamAPI(
 		steamAPI,
+		cacheStore,
+		adapters.SystemClock{},
+		appLogger,
+		cfg.Cache.MaxEntries,
+		cfg.Cache.SearchTTL,
+		cfg.Cache.PriceTTL,
+	)
+	appMetrics.RegisterCacheStats(func() (uint64, uint64, int) {
+		stats := cachedSteamAPI.Stats()
+		return stats.Hits, stats.Misses, stats.Entries
+	})
+	aiQueriesAPI, err := adapters.NewAiQueriesAPI(cfg.AI, appLogger)
+	if err != nil {
+		log.Fatalf("Некорректная конфигурация: %v", err)
+	}
+	// Запросы к AI платные, поэтому у каждого пользователя отдельный, более строгий лимит
+	aiAPI := adapters.NewInstrumentedAiAPI(
+		adapters.NewRateLimitedAiAPI(
+			aiQueriesAPI,
+			ratelimit.NewLimiter(adapters.SystemClock{}, cfg.App.AIRateLimitBurst, cfg.App.AIRateLimitRefill),
+		),
+		adapters.SystemClock{},
+		appMetrics,
+	)
+	gameRepo := adapters.NewPostgresGameRepository(dbPool)
+	snapshotRepo := adapters.NewPostgresPriceSnapshotRepository(dbPool)
+	sortMode, err := presenters.ParseRegionSortMode(cfg.App.RegionSortMode)
+	if err != nil {
+		log.Fatalf("Некорректная конфигурация: %v", err)
+	}
+	formatter := presenters.NewMessageFormatter(sortMode)
+
+	// Фоновые задачи останавливаются вместе с ботом при отмене ctx (Ctrl+C)
+	appScheduler := scheduler.NewScheduler(adapters.SystemClock{}, appLogger)
+
+	// Курсы валют загружаются сразу и затем обновляются по расписанию
+	ratesProvider, err := newCurrencyRatesProvider(cfg.Currency)
+	if err != nil {
+		log.Fatalf("Некорректная конфигурация: %v", err)
+	}
+	currencyRates := usecases.NewCurrencyRatesService(ratesProvider, cfg.App.CurrencyRates)
+	go appScheduler.Run(ctx, "currency-rates", cfg.Currency.RefreshInterval, currencyRates.Refresh)
+
+	// Каталог приложений Steam строится в фоне: до первой загрузки бот работает без него
+	catalogProvider, err := newAppCatalogProvider(cfg.Catalog)
+	if err != nil {
+		log.Fatalf("Некорректная конфигурация: %v", err)
+	}
+	appCatalog := usecases.NewAppCatalogService(catalogProvider)
+	if catalogProvider != nil {
+		go appScheduler.Run(ctx, "app-catalog", cfg.Catalog.RefreshInterval, appCatalog.Refresh)
+	}
+
+	settingsService := usecases.NewUserSettingsService(
+		adapters.NewPostgresUserSettingsRepository(dbPool),
+		cfg.App.SupportedCountries,
+		cfg.App.HomeCurrencies,
+	)
+
+	telegramHandler := handlers.NewTelegramHandler(
+		cachedSteamAPI,
 		aiAPI,
+		gameRepo,
+		snapshotRepo,
 		formatter,
 		appLogger,
 		cfg.App.SupportedCountries,
-		cfg.App.CurrencyRates,
+		currencyRates,
+		appCatalog,
+		settingsService,
+		ratelimit.NewLimiter(adapters.SystemClock{}, cfg.App.RateLimitBurst, cfg.App.RateLimitRefill),
+		// Inline-запросы приходят на каждый набранный символ — у них отдельный, более мягкий лимит
+		ratelimit.NewLimiter(adapters.SystemClock{}, cfg.App.InlineRateLimitBurst, cfg.App.InlineRateLimitRefill),
+		adapters.SystemClock{},
+		appMetrics,
+		cfg.App.RegionWorkers,
+		cfg.App.RegionTimeout,
+		cfg.App.MaxSearchResults,
+		cfg.App.InlineCacheTime,
 	)
 
 	// Инициализируем бота
 	opts := []bot.Option{
 		bot.WithDefaultHandler(telegramHandler.Handle),
+		// Ошибки библиотеки (например, сбои getUpdates) пишем в общий лог
+		bot.WithErrorsHandler(func(err error) {
+			appLogger.Error(ctx, "Ошибка Telegram Bot API", err)
+		}),
+	}
+	if cfg.Telegram.Mode == "webhook" {
+		opts = append(opts, bot.WithWebhookSecretToken(cfg.Telegram.WebhookSecret))
+	}
+	if appMetrics != nil {
+		// Клиент как у библиотеки по умолчанию, но с подсчетом ошибок Bot API
+		opts = append(opts, bot.WithHTTPClient(telegramPollTimeout, &http.Client{
+			Timeout:   telegramPollTimeout,
+			Transport: appMetrics.TelegramTransport(http.DefaultTransport),
+		}))
 	}
 
 	b, err := bot.New(cfg.Telegram.BotToken, opts...)
 	if err != nil {
-		appLogger.Error("Ошибка создания бота", err)
+		appLogger.Error(ctx, "Ошибка создания бота", err)
 		log.Fatalf("Не удалось создать бота: %v", err)
 	}
 
-	appLogger.Info("Бот запущен и готов к работе")
-	b.Start(ctx)
+	// Имя бота нужно, чтобы в группах отличать свои команды (/find@BotName) от чужих
+	me, err := b.GetMe(ctx)
+	if err != nil {
+		appLogger.Error(ctx, "Ошибка получения информации о боте", err)
+	} else {
+		telegramHandler.SetBotUsername(me.Username)
+	}
+	// Меню команд в Telegram не критично для работы бота — ошибку только логируем
+	if err := telegramHandler.RegisterBotCommands(ctx, b); err != nil {
+		appLogger.Error(ctx, "Ошибка регистрации команд бота", err)
+	}
+
+	// Запускаем фоновую проверку цен отслеживаемых игр
+	priceWatcher := usecases.NewPriceWatcherService(
+		steamAPI,
+		gameRepo,
+		snapshotRepo,
+		handlers.NewTelegramNotifier(b, formatter, settingsService),
+		adapters.SystemClock{},
+		appLogger,
+		cfg.App.SupportedCountries,
+		cfg.Watcher.RecheckAfter,
+		cfg.Watcher.BatchSize,
+	)
+	go appScheduler.Run(ctx, "price-watcher", cfg.Watcher.Interval, func(ctx context.Context) error {
+		_, err := priceWatcher.CheckDueGames(ctx)
+		return err
+	})
+
+	if cacheStore != nil {
+		go appScheduler.Run(ctx, "cache-cleanup", time.Hour, cacheStore.DeleteExpired)
+	}
+
+	if appMetrics != nil {
+		go runMetricsServer(ctx, cfg.Metrics, appMetrics, dbPool, appLogger)
+	}
+
+	appLogger.Info(ctx, "Бот запущен и готов к работе", "mode", cfg.Telegram.Mode)
+	if cfg.Telegram.Mode != "webhook" {
+		b.Start(ctx)
+		return
+	}
+
+	if err := runWebhook(ctx, b, cfg.Telegram, appLogger); err != nil {
+		appLogger.Error(ctx, "Ошибка работы вебхука", err)
+		log.Fatalf("Не удалось запустить вебхук: %v", err)
+	}
+}
+
+// runMetricsServer обслуживает /metrics, /healthz и /readyz, пока не отменен ctx.
+// Ошибка сервера метрик не останавливает бота — она только логируется.
+func runMetricsServer(ctx context.Context, cfg config.MetricsConfig, appMetrics *metrics.Metrics, dbPool *pgxpool.Pool, appLogger logger.Logger) {
+	httpServer := server.NewServer(cfg.ListenAddr, appLogger)
+	httpServer.Handle("GET /metrics", appMetrics.Handler())
+	httpServer.Handle("GET /healthz", server.HealthHandler())
+	httpServer.Handle("GET /readyz", server.ReadyHandler(map[string]server.Check{
+		"database": dbPool.Ping,
+		"steam":    appMetrics.SteamCheck(cfg.SteamMaxAge),
+	}))
+
+	if err := httpServer.Run(ctx); err != nil {
+		appLogger.Error(ctx, "Ошибка сервера метрик", err)
+	}
+}
+
+// runWebhook принимает обновления через вебхук, пока не отменен ctx.
+// При запуске регистрирует вебхук в Telegram (setWebhook), а при остановке удаляет его (deleteWebhook),
+// чтобы бот можно было снова запустить в режиме polling.
+func runWebhook(ctx context.Context, b *bot.Bot, cfg config.TelegramConfig, appLogger logger.Logger) error {
+	webhookURL, err := url.Parse(cfg.WebhookURL)
+	if err != nil {
+		return fmt.Errorf("некорректный TELEGRAM_WEBHOOK_URL: %w", err)
+	}
+	path := webhookURL.Path
+	if path == "" {
+		path = "/"
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	httpServer := server.NewServer(cfg.WebhookListenAddr, appLogger)
+	httpServer.Handle("POST "+path, handlers.NewWebhookHandler(b, cfg.WebhookSecret, appLogger))
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- httpServer.Run(ctx)
+	}()
+	go b.StartWebhook(ctx)
+
+	_, err = b.SetWebhook(ctx, &bot.SetWebhookParams{
+		URL:         cfg.WebhookURL,
+		SecretToken: cfg.WebhookSecret,
+	})
+	if err != nil {
+		return fmt.Errorf("не удалось зарегистрировать вебхук: %w", err)
+	}
+	appLogger.Info(ctx, "Вебхук зарегистрирован", "path", path, "addr", cfg.WebhookListenAddr)
+
+	err = <-serverErr
+
+	// ctx уже отменен, поэтому для удаления вебхука нужен отдельный контекст
+	deleteCtx, deleteCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer deleteCancel()
+	if _, deleteErr := b.DeleteWebhook(deleteCtx, &bot.DeleteWebhookParams{}); deleteErr != nil {
+		appLogger.Error(ctx, "Ошибка удаления вебхука", deleteErr)
+	} else {
+		appLogger.Info(ctx, "Вебхук удален")
+	}
+
+	return err
+}
+
+// newCurrencyRatesProvider создает источник курсов валют по конфигурации.
+// Для "static" возвращает nil — используются только курсы из конфигурации.
+func newCurrencyRatesProvider(cfg config.CurrencyConfig) (interfaces.CurrencyRatesProvider, error) {
+	switch cfg.Provider {
+	case "cbr":
+		return adapters.NewCBRRatesAPI(urlOrDefault(cfg.URL, adapters.CBRDailyURL), cfg.Timeout), nil
+	case "ecb":
+		return adapters.NewECBRatesAPI(urlOrDefault(cfg.URL, adapters.ECBDailyURL), cfg.Timeout), nil
+	case "static":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("неизвестный источник курсов валют: %q", cfg.Provider)
+	}
+}
+
+// newAppCatalogProvider создает источник списка приложений Steam по конфигурации.
+// Для "none" возвращает nil — каталог отключен.
+func newAppCatalogProvider(cfg config.CatalogConfig) (interfaces.AppCatalogProvider, error) {
+	switch cfg.Provider {
+	case "steam":
+		return adapters.NewSteamAppListAPI(urlOrDefault(cfg.URL, adapters.SteamAppListURL), cfg.Timeout), nil
+	case "file":
+		if cfg.File == "" {
+			return nil, fmt.Errorf("CATALOG_FILE не установлен")
+		}
+		return adapters.NewFileAppList(cfg.File), nil
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("неизвестный источник каталога приложений: %q", cfg.Provider)
+	}
+}
+
+// newCacheStore создает постоянное хранилище кэша по конфигурации.
+// Для "memory" возвращает nil — кэш живет только в памяти.
+func newCacheStore(cfg config.CacheConfig, pool *pgxpool.Pool) (interfaces.CacheStore, error) {
+	switch cfg.Store {
+	case "postgres":
+		return adapters.NewPostgresCacheStore(pool), nil
+	case "file":
+		store, err := adapters.NewFileCacheStore(cfg.FileDir)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case "memory":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("неизвестное хранилище кэша: %q", cfg.Store)
+	}
+}
+
+// urlOrDefault возвращает url, а если он пустой — defaultURL
+func urlOrDefault(url, defaultURL string) string {
+	if url != "" {
+		return url
+	}
+	return defaultURL
 }
 
 // loadConfig загружает конфигурацию с учетом окружения
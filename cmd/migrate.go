@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/MaximVod/steambotgo/internal/config"
+	"github.com/MaximVod/steambotgo/internal/database"
+	"github.com/MaximVod/steambotgo/migrations"
+)
+
+// migrateUsage — подсказка по команде migrate
+const migrateUsage = "использование: migrate up | migrate down [N] | migrate status"
+
+// runMigrateCommand выполняет команду migrate: up применяет все новые миграции,
+// down [N] откатывает N последних (по умолчанию одну), status выводит состояние схемы
+func runMigrateCommand(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	if err := loadEnvFile(); err != nil {
+		log.Printf("Предупреждение: не удалось загрузить .env файл: %v", err)
+	}
+
+	dbPool, err := database.InitDB(ctx, config.LoadDatabase().URL)
+	if err != nil {
+		return fmt.Errorf("не удалось подключиться к базе данных: %w", err)
+	}
+	defer database.Close(dbPool)
+
+	migrator, err := database.NewMigrator(dbPool, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("применена %s\n", migration)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("схема актуальна")
+		}
+		return nil
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("некорректное число миграций для отката: %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Printf("откачена %s\n", migration)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Println("нет примененных миграций")
+		}
+		return nil
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		return printMigrationStatus(statuses)
+
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+// printMigrationStatus выводит таблицу миграций
+func printMigrationStatus(statuses []database.MigrationStatus) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "МИГРАЦИЯ\tСОСТОЯНИЕ\tПРИМЕНЕНА")
+	for _, status := range statuses {
+		state, appliedAt := "не применена", "-"
+		if status.Applied {
+			state = "применена"
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if status.Drifted {
+			state = "файл изменен после применения"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", status.Migration, state, appliedAt)
+	}
+	return w.Flush()
+}
@@ -7,28 +7,158 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
+	"net/url"
+	"sort"
 	"strings"
 
+	"github.com/MaximVod/steambotgo/internal/config"
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
 	"github.com/MaximVod/steambotgo/internal/logger"
 )
 
+const (
+	// maxAIErrorBody — сколько байт тела ответа с ошибкой попадает в текст ошибки
+	maxAIErrorBody = 512
+	// maxAICandidates — сколько названий-кандидатов просить у модели
+	maxAICandidates = 3
+	// maxDiscoveryTitles — сколько игр просить у модели для подборки (каждая проверяется в Steam)
+	maxDiscoveryTitles = 10
+	// maxDiscoveryGenres — сколько жанров подборки сохранять (они попадают в логи)
+	maxDiscoveryGenres = 5
+)
+
+// discoveryPlatforms — платформы, которые понимает фильтр подборки
+var discoveryPlatforms = map[string]bool{"windows": true, "mac": true, "linux": true}
+
+// discoverySchema — JSON Schema ответа модели на запрос подборки игр.
+// В строгом режиме все поля обязательны, поэтому «не указано» передается нулем или пустой строкой.
+var discoverySchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"genres":       map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		"max_price":    map[string]interface{}{"type": "number"},
+		"currency":     map[string]interface{}{"type": "string"},
+		"country_code": map[string]interface{}{"type": "string"},
+		"platforms": map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "string", "enum": []string{"windows", "mac", "linux"}},
+		},
+		"controller_support": map[string]interface{}{"type": "boolean"},
+		"titles":             map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+	},
+	"required":             []string{"genres", "max_price", "currency", "country_code", "platforms", "controller_support", "titles"},
+	"additionalProperties": false,
+}
+
+// aiCandidatesSchema — JSON Schema ответа модели: названия игр с уверенностью
+var aiCandidatesSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"candidates": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"title":      map[string]interface{}{"type": "string"},
+					"confidence": map[string]interface{}{"type": "number"},
+				},
+				"required":             []string{"title", "confidence"},
+				"additionalProperties": false,
+			},
+		},
+	},
+	"required":             []string{"candidates"},
+	"additionalProperties": false,
+}
+
+// AiQueriesAPI — клиент OpenAI-совместимого API (/chat/completions)
 type AiQueriesAPI struct {
-	baseURL string
-	client  *http.Client
+	endpoint    string
+	model       string
+	apiKey      string
+	temperature float64
+	client      *http.Client
+	logger      logger.Logger
 }
 
-func (f AiQueriesAPI) SearchGamesByUserQuery(ctx context.Context, query string) (string, error) {
-	systemPrompt := "Ты — помощник, который исправляет названия видеоигр. Пользователь вводит неточное название. Твоя задача — предложить наиболее вероятное исправленное название из известных видеоигр, даже если уверенность не 100%. Верни ТОЛЬКО одно название. НЕ используй NOT_FOUND, если есть разумное предположение."
+// NewAiQueriesAPI создает клиент AI и проверяет настройки:
+// адрес должен быть http(s) URL, модель — непустой, таймаут — положительным,
+// температура — от 0 до 2.
+func NewAiQueriesAPI(cfg config.AIConfig, logger logger.Logger) (*AiQueriesAPI, error) {
+	baseURL, err := url.Parse(cfg.BaseURL)
+	if err != nil || (baseURL.Scheme != "http" && baseURL.Scheme != "https") || baseURL.Host == "" {
+		return nil, fmt.Errorf("некорректный AI_BASE_URL: %q", cfg.BaseURL)
+	}
+	if strings.TrimSpace(cfg.Model) == "" {
+		return nil, fmt.Errorf("AI_MODEL не установлен")
+	}
+	if cfg.Timeout <= 0 {
+		return nil, fmt.Errorf("AI_TIMEOUT должен быть больше нуля: %s", cfg.Timeout)
+	}
+	if cfg.Temperature < 0 || cfg.Temperature > 2 {
+		return nil, fmt.Errorf("AI_TEMPERATURE должна быть от 0 до 2: %g", cfg.Temperature)
+	}
 
-	appLogger := logger.New()
+	return &AiQueriesAPI{
+		endpoint:    strings.TrimSuffix(cfg.BaseURL, "/") + "/chat/completions",
+		model:       cfg.Model,
+		apiKey:      cfg.APIKey,
+		temperature: cfg.Temperature,
+		client:      &http.Client{Timeout: cfg.Timeout},
+		logger:      logger,
+	}, nil
+}
+
+// SearchGamesByUserQuery реализует interfaces.AiAPI.
+func (f *AiQueriesAPI) SearchGamesByUserQuery(ctx context.Context, query string) ([]entities.AICandidate, error) {
+	systemPrompt := fmt.Sprintf("Ты — помощник, который исправляет названия видеоигр. Пользователь вводит неточное название. Твоя задача — предложить до %d наиболее вероятных официальных названий из известных видеоигр в том виде, как они записаны в Steam, даже если уверенность не 100%%. Для каждого укажи уверенность от 0 до 1 и отсортируй по убыванию уверенности. Если разумных предположений нет, верни пустой список.", maxAICandidates)
 
 	// Очищаем query от лишних пробелов
 	query = strings.TrimSpace(query)
-	appLogger.Info("AI запрос", "original_query", query)
+	f.logger.Info(ctx, "AI запрос", "original_query", query)
 
+	content, err := f.chatCompletion(ctx, systemPrompt, query, "game_titles", aiCandidatesSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := parseAICandidates(content)
+	if err != nil {
+		return nil, err
+	}
+	f.logger.Info(ctx, "AI предложил названия игры", "original", query, "candidates", candidates)
+
+	return candidates, nil
+}
+
+// ParseDiscoveryQuery реализует interfaces.AiAPI.
+func (f *AiQueriesAPI) ParseDiscoveryQuery(ctx context.Context, request string) (*entities.DiscoveryFilters, error) {
+	systemPrompt := fmt.Sprintf("Ты — помощник по подбору игр в Steam. Преобразуй запрос пользователя в фильтры. genres — жанры и теги на английском, как в Steam (co-op, roguelike). max_price — максимальная цена, currency — ее валюта в ISO 4217 (RUB, USD); если цена не указана, max_price = 0 и currency — пустая строка. country_code — страна магазина в ISO 3166-1 alpha-2 (Турция — TR) или пустая строка, если страна не указана. platforms — нужные платформы из windows, mac, linux или пустой список. controller_support — нужна ли поддержка геймпада. titles — до %d реально существующих игр из Steam, которые подходят под запрос, с точными названиями как в Steam, сначала самые подходящие; учитывай цену и не предлагай игры, которые обычно стоят дороже.", maxDiscoveryTitles)
+
+	request = strings.TrimSpace(request)
+	f.logger.Info(ctx, "AI запрос подборки", "request", request)
+
+	content, err := f.chatCompletion(ctx, systemPrompt, request, "game_discovery", discoverySchema)
+	if err != nil {
+		return nil, err
+	}
+
+	filters, err := parseDiscoveryFilters(content)
+	if err != nil {
+		return nil, err
+	}
+	f.logger.Info(ctx, "AI подобрал игры", "request", request, "filters", filters)
+
+	return filters, nil
+}
+
+// chatCompletion отправляет запрос /chat/completions с ответом в формате JSON по schema
+// и возвращает содержимое ответа модели
+func (f *AiQueriesAPI) chatCompletion(ctx context.Context, systemPrompt, userContent, schemaName string, schema map[string]interface{}) (string, error) {
 	payload := map[string]interface{}{
-		"model": "gpt-4o-mini",
+		"model":       f.model,
+		"temperature": f.temperature,
 		"messages": []map[string]string{
 			{
 				"role":    "system",
@@ -36,7 +166,16 @@ func (f AiQueriesAPI) SearchGamesByUserQuery(ctx context.Context, query string)
 			},
 			{
 				"role":    "user",
-				"content": query,
+				"content": userContent,
+			},
+		},
+		// Structured output: сервер обязан вернуть JSON по схеме, но ответ все равно проверяется
+		"response_format": map[string]interface{}{
+			"type": "json_schema",
+			"json_schema": map[string]interface{}{
+				"name":   schemaName,
+				"strict": true,
+				"schema": schema,
 			},
 		},
 	}
@@ -47,29 +186,29 @@ func (f AiQueriesAPI) SearchGamesByUserQuery(ctx context.Context, query string)
 	}
 
 	// Логируем отправляемый запрос для отладки
-	appLogger.Info("AI запрос payload", "payload", string(body))
+	f.logger.Debug(ctx, "AI запрос payload", "payload", string(body))
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.artemox.com/v1/chat/completions", bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", f.endpoint, bytes.NewBuffer(body))
 	if err != nil {
 		return "", fmt.Errorf("не удалось создать запрос: %w", err)
 	}
 
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
-		return "", fmt.Errorf("OPENAI_API_KEY не установлен")
+	// Локальным серверам (Ollama) ключ не нужен
+	if f.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+f.apiKey)
 	}
-
-	req.Header.Set("Authorization", "Bearer "+apiKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := f.client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("ошибка при выполнении запроса к AI API: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("неожиданный статус от AI API: %d", resp.StatusCode)
+		// Текст ошибки сервера подсказывает причину: неверный ключ, неизвестная модель
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxAIErrorBody))
+		return "", fmt.Errorf("неожиданный статус от AI API: %d: %s", resp.StatusCode, strings.TrimSpace(string(errBody)))
 	}
 
 	raw, err := io.ReadAll(resp.Body)
@@ -78,7 +217,7 @@ func (f AiQueriesAPI) SearchGamesByUserQuery(ctx context.Context, query string)
 	}
 
 	// Логируем ответ для отладки
-	appLogger.Info("AI ответ", "response", string(raw))
+	f.logger.Debug(ctx, "AI ответ", "response", string(raw))
 
 	// Парсинг ответа в формате chat.completion
 	var result struct {
@@ -98,8 +237,136 @@ func (f AiQueriesAPI) SearchGamesByUserQuery(ctx context.Context, query string)
 		return "", fmt.Errorf("AI вернул пустой ответ")
 	}
 
-	correctedName := strings.TrimSpace(result.Choices[0].Message.Content)
-	appLogger.Info("AI исправил название игры", "original", query, "corrected", correctedName)
+	// Модели без поддержки structured output иногда оборачивают JSON в markdown блок
+	content := strings.TrimSpace(result.Choices[0].Message.Content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+
+	return content, nil
+}
+
+// parseAICandidates проверяет ответ модели: JSON по схеме aiCandidatesSchema.
+// Пустые и повторяющиеся названия отбрасываются, уверенность ограничивается
+// диапазоном 0..1, кандидаты сортируются по убыванию уверенности.
+func parseAICandidates(content string) ([]entities.AICandidate, error) {
+	var output struct {
+		Candidates []struct {
+			Title      string   `json:"title"`
+			Confidence *float64 `json:"confidence"`
+		} `json:"candidates"`
+	}
+	if err := json.Unmarshal([]byte(content), &output); err != nil {
+		return nil, fmt.Errorf("AI вернул ответ не по схеме: %w", err)
+	}
+	// Пустой список — нормальный ответ, а отсутствие поля — нет
+	if output.Candidates == nil {
+		return nil, fmt.Errorf("AI вернул ответ без поля candidates")
+	}
+
+	candidates := make([]entities.AICandidate, 0, len(output.Candidates))
+	seen := make(map[string]bool, len(output.Candidates))
+	for _, candidate := range output.Candidates {
+		title := strings.TrimSpace(candidate.Title)
+		key := strings.ToLower(title)
+		if title == "" || candidate.Confidence == nil || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		confidence := min(max(*candidate.Confidence, 0), 1)
+		candidates = append(candidates, entities.AICandidate{Title: title, Confidence: confidence})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Confidence > candidates[j].Confidence
+	})
+	if len(candidates) > maxAICandidates {
+		candidates = candidates[:maxAICandidates]
+	}
+
+	return candidates, nil
+}
+
+// parseDiscoveryFilters проверяет ответ модели: JSON по схеме discoverySchema.
+// Неизвестные платформы, некорректные коды валюты и страны отбрасываются,
+// повторяющиеся названия и жанры удаляются, число названий ограничивается maxDiscoveryTitles.
+func parseDiscoveryFilters(content string) (*entities.DiscoveryFilters, error) {
+	var output struct {
+		Genres            []string `json:"genres"`
+		MaxPrice          float64  `json:"max_price"`
+		Currency          string   `json:"currency"`
+		CountryCode       string   `json:"country_code"`
+		Platforms         []string `json:"platforms"`
+		ControllerSupport bool     `json:"controller_support"`
+		Titles            []string `json:"titles"`
+	}
+	if err := json.Unmarshal([]byte(content), &output); err != nil {
+		return nil, fmt.Errorf("AI вернул ответ не по схеме: %w", err)
+	}
+	// Пустой список — нормальный ответ, а отсутствие поля — нет
+	if output.Titles == nil {
+		return nil, fmt.Errorf("AI вернул ответ без поля titles")
+	}
+
+	filters := &entities.DiscoveryFilters{
+		Genres:            uniqueStrings(output.Genres, maxDiscoveryGenres),
+		ControllerSupport: output.ControllerSupport,
+		Titles:            uniqueStrings(output.Titles, maxDiscoveryTitles),
+	}
+
+	if output.MaxPrice > 0 {
+		filters.MaxPrice = output.MaxPrice
+		if currency := strings.ToUpper(strings.TrimSpace(output.Currency)); isLetterCode(currency, 3) {
+			filters.MaxPriceCurrency = currency
+		}
+	}
+	if countryCode := strings.ToUpper(strings.TrimSpace(output.CountryCode)); isLetterCode(countryCode, 2) {
+		filters.CountryCode = countryCode
+	}
+
+	for _, platform := range uniqueStrings(output.Platforms, len(discoveryPlatforms)) {
+		platform = strings.ToLower(platform)
+		if discoveryPlatforms[platform] {
+			filters.Platforms = append(filters.Platforms, platform)
+		}
+	}
 
-	return correctedName, nil
+	return filters, nil
 }
+
+// uniqueStrings убирает пустые и повторяющиеся (без учета регистра) строки
+// и оставляет не больше limit первых
+func uniqueStrings(values []string, limit int) []string {
+	result := make([]string, 0, min(len(values), limit))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		key := strings.ToLower(value)
+		if value == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, value)
+		if len(result) == limit {
+			break
+		}
+	}
+	return result
+}
+
+// isLetterCode проверяет, что code — n латинских букв в верхнем регистре (код страны или валюты)
+func isLetterCode(code string, n int) bool {
+	if len(code) != n {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Компиляторная проверка реализации интерфейса.
+var _ interfaces.AiAPI = (*AiQueriesAPI)(nil)
@@ -0,0 +1,182 @@
+package adapters
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresCacheStore хранит кэш в PostgreSQL (таблица steam_cache).
+type PostgresCacheStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresCacheStore(pool *pgxpool.Pool) *PostgresCacheStore {
+	return &PostgresCacheStore{
+		pool: pool,
+	}
+}
+
+// Get реализует interfaces.CacheStore.
+func (s *PostgresCacheStore) Get(ctx context.Context, key string) ([]byte, time.Time, bool, error) {
+	var (
+		value     []byte
+		expiresAt time.Time
+	)
+	err := s.pool.QueryRow(ctx,
+		`SELECT value, expires_at FROM steam_cache WHERE key = $1 AND expires_at > NOW()`,
+		key,
+	).Scan(&value, &expiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, time.Time{}, false, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, false, fmt.Errorf("не удалось прочитать кэш: %w", err)
+	}
+
+	return value, expiresAt, true, nil
+}
+
+// Set реализует interfaces.CacheStore.
+func (s *PostgresCacheStore) Set(ctx context.Context, key string, value []byte, expiresAt time.Time) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO steam_cache (key, value, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at`,
+		key, value, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("не удалось записать кэш: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired реализует interfaces.CacheStore.
+func (s *PostgresCacheStore) DeleteExpired(ctx context.Context) error {
+	if _, err := s.pool.Exec(ctx, `DELETE FROM steam_cache WHERE expires_at <= NOW()`); err != nil {
+		return fmt.Errorf("не удалось очистить кэш: %w", err)
+	}
+
+	return nil
+}
+
+// FileCacheStore хранит кэш в файлах каталога — по файлу на ключ.
+// Подходит для запуска без базы данных или на одном сервере.
+type FileCacheStore struct {
+	dir string
+}
+
+// fileCacheEntry — содержимое файла кэша
+type fileCacheEntry struct {
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewFileCacheStore создает файловое хранилище кэша в каталоге dir (создается при необходимости).
+func NewFileCacheStore(dir string) (*FileCacheStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("не удалось создать каталог кэша %s: %w", dir, err)
+	}
+
+	return &FileCacheStore{
+		dir: dir,
+	}, nil
+}
+
+// Get реализует interfaces.CacheStore.
+func (s *FileCacheStore) Get(_ context.Context, key string) ([]byte, time.Time, bool, error) {
+	raw, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, time.Time{}, false, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, false, fmt.Errorf("не удалось прочитать кэш: %w", err)
+	}
+
+	var entry fileCacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		// Поврежденный файл считаем отсутствующим — он перезапишется
+		return nil, time.Time{}, false, nil
+	}
+	if !time.Now().Before(entry.ExpiresAt) {
+		return nil, time.Time{}, false, nil
+	}
+
+	return entry.Value, entry.ExpiresAt, true, nil
+}
+
+// Set реализует interfaces.CacheStore.
+func (s *FileCacheStore) Set(_ context.Context, key string, value []byte, expiresAt time.Time) error {
+	raw, err := json.Marshal(fileCacheEntry{Value: value, ExpiresAt: expiresAt})
+	if err != nil {
+		return fmt.Errorf("не удалось сериализовать кэш: %w", err)
+	}
+
+	// Пишем во временный файл и переименовываем, чтобы параллельное чтение
+	// никогда не увидело наполовину записанный файл
+	tmp, err := os.CreateTemp(s.dir, "tmp-*")
+	if err != nil {
+		return fmt.Errorf("не удалось записать кэш: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("не удалось записать кэш: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("не удалось записать кэш: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		return fmt.Errorf("не удалось записать кэш: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired реализует interfaces.CacheStore.
+func (s *FileCacheStore) DeleteExpired(_ context.Context) error {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("не удалось очистить кэш: %w", err)
+	}
+
+	now := time.Now()
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		var entry fileCacheEntry
+		if err := json.Unmarshal(raw, &entry); err != nil || !now.Before(entry.ExpiresAt) {
+			_ = os.Remove(file)
+		}
+	}
+
+	return nil
+}
+
+// path возвращает путь к файлу ключа.
+// Ключ хэшируется, так как может содержать символы, недопустимые в имени файла.
+func (s *FileCacheStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Компиляторная проверка реализации интерфейса.
+var (
+	_ interfaces.CacheStore = (*PostgresCacheStore)(nil)
+	_ interfaces.CacheStore = (*FileCacheStore)(nil)
+)
@@ -0,0 +1,295 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+	"github.com/MaximVod/steambotgo/internal/logger"
+	"golang.org/x/sync/singleflight"
+)
+
+// sharedLoadTimeout — дедлайн общего запроса к Steam, на который ждут одновременные вызовы.
+// Запрос не зависит от контекста первого вызова: его таймаут или отмена не должны
+// приводить к ошибке у остальных ожидающих.
+const sharedLoadTimeout = 30 * time.Second
+
+// CacheStats — счетчики обращений к кэшу для мониторинга.
+type CacheStats struct {
+	Hits    uint64 // ответ взят из памяти или постоянного хранилища
+	Misses  uint64 // пришлось обращаться к Steam
+	Entries int    // записей в памяти
+}
+
+// CachedSteamAPI кэширует ответы Steam перед другой реализацией interfaces.SteamAPI.
+// Поиск и цены хранятся с разным временем жизни, одинаковые одновременные запросы
+// объединяются в один (singleflight), а при наличии store ответы переживают перезапуск.
+type CachedSteamAPI struct {
+	next      interfaces.SteamAPI
+	store     interfaces.CacheStore // может быть nil — тогда кэш только в памяти
+	clock     interfaces.Clock
+	logger    logger.Logger
+	memory    *lruCache
+	group     singleflight.Group
+	searchTTL time.Duration
+	priceTTL  time.Duration
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func NewCachedSteamAPI(
+	next interfaces.SteamAPI,
+	store interfaces.CacheStore,
+	clock interfaces.Clock,
+	logger logger.Logger,
+	maxEntries int,
+	searchTTL time.Duration,
+	priceTTL time.Duration,
+) *CachedSteamAPI {
+	return &CachedSteamAPI{
+		next:      next,
+		store:     store,
+		clock:     clock,
+		logger:    logger,
+		memory:    newLRUCache(maxEntries),
+		searchTTL: searchTTL,
+		priceTTL:  priceTTL,
+	}
+}
+
+// Stats возвращает счетчики попаданий и промахов кэша.
+func (c *CachedSteamAPI) Stats() CacheStats {
+	return CacheStats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Entries: c.memory.Len(),
+	}
+}
+
+// SearchGamesByName реализует interfaces.SteamAPI.
+func (c *CachedSteamAPI) SearchGamesByName(ctx context.Context, query string) ([]entities.SteamItem, error) {
+	key := "search:" + strings.ToLower(strings.TrimSpace(query))
+	items, err := getOrLoad(ctx, c, key, c.searchTTL, func(ctx context.Context) ([]entities.SteamItem, error) {
+		return c.next.SearchGamesByName(ctx, query)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Возвращаем копию, чтобы вызывающий код не изменил закэшированный срез
+	return slices.Clone(items), nil
+}
+
+// SearchGameByQuery реализует interfaces.SteamAPI.
+// Использует тот же кэш, что и SearchGamesByName.
+func (c *CachedSteamAPI) SearchGameByQuery(ctx context.Context, query string) (*entities.SteamItem, error) {
+	items, err := c.SearchGamesByName(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	return &items[0], nil
+}
+
+// GetGamePricesByCountryCode реализует interfaces.SteamAPI.
+func (c *CachedSteamAPI) GetGamePricesByCountryCode(ctx context.Context, query string, countryCode string, gameID int) (*entities.SteamItem, error) {
+	key := fmt.Sprintf("country-search:%s:%d:%s", countryCode, gameID, strings.ToLower(strings.TrimSpace(query)))
+	item, err := getOrLoad(ctx, c, key, c.priceTTL, func(ctx context.Context) (*entities.SteamItem, error) {
+		return c.next.GetGamePricesByCountryCode(ctx, query, countryCode, gameID)
+	})
+	if err != nil || item == nil {
+		return nil, err
+	}
+
+	copied := *item
+	return &copied, nil
+}
+
+// appPriceEntry — закэшированная цена одного приложения в регионе
+type appPriceEntry struct {
+	Available bool                `json:"available"`
+	Price     *entities.PriceInfo `json:"price"`
+}
+
+// GetAppPrices реализует interfaces.SteamAPI.
+// Цены кэшируются по отдельности для каждого App ID, поэтому пачки с разным
+// составом приложений используют общий кэш, а в Steam запрашиваются только недостающие.
+func (c *CachedSteamAPI) GetAppPrices(ctx context.Context, appIDs []int, countryCode string) (map[int]*entities.PriceInfo, error) {
+	prices := make(map[int]*entities.PriceInfo, len(appIDs))
+
+	var missing []int
+	for _, id := range appIDs {
+		entry, ok := lookup[appPriceEntry](ctx, c, appPriceKey(countryCode, id))
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		c.hits.Add(1)
+		if entry.Available {
+			prices[id] = entry.Price
+		}
+	}
+
+	if len(missing) == 0 {
+		return prices, nil
+	}
+
+	// Одинаковые пачки от разных пользователей объединяются в один запрос
+	slices.Sort(missing)
+	ids := make([]string, 0, len(missing))
+	for _, id := range missing {
+		ids = append(ids, strconv.Itoa(id))
+	}
+	batchKey := fmt.Sprintf("app-prices:%s:%s", countryCode, strings.Join(ids, ","))
+
+	loaded, err := c.do(ctx, batchKey, func(loadCtx context.Context) (any, error) {
+		c.misses.Add(uint64(len(missing)))
+
+		loaded, err := c.next.GetAppPrices(loadCtx, missing, countryCode)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range missing {
+			price, available := loaded[id]
+			c.save(loadCtx, appPriceKey(countryCode, id), appPriceEntry{Available: available, Price: price}, c.priceTTL)
+		}
+		return loaded, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for id, price := range loaded.(map[int]*entities.PriceInfo) {
+		prices[id] = price
+	}
+
+	return prices, nil
+}
+
+// getOrLoad возвращает значение из кэша или загружает его через load.
+// Одновременные загрузки одного ключа объединяются в одну.
+func getOrLoad[T any](ctx context.Context, c *CachedSteamAPI, key string, ttl time.Duration, load func(context.Context) (T, error)) (T, error) {
+	if value, ok := lookup[T](ctx, c, key); ok {
+		c.hits.Add(1)
+		return value, nil
+	}
+
+	loaded := false
+	value, err := c.do(ctx, key, func(loadCtx context.Context) (any, error) {
+		// Функция выполняется только у первого из одновременных вызовов
+		loaded = true
+		c.misses.Add(1)
+
+		value, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		c.save(loadCtx, key, value, ttl)
+		return value, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	// Ожидавшие чужого запроса к Steam тоже не обращались к нему сами
+	if !loaded {
+		c.hits.Add(1)
+	}
+
+	return value.(T), nil
+}
+
+// do объединяет одновременные загрузки ключа в одну (singleflight).
+// Загрузка выполняется на контексте без отмены со своим таймаутом sharedLoadTimeout,
+// а каждый вызов ждет ее, пока не истечет его собственный ctx.
+func (c *CachedSteamAPI) do(ctx context.Context, key string, load func(context.Context) (any, error)) (any, error) {
+	results := c.group.DoChan(key, func() (any, error) {
+		// Значения контекста (correlation_id для логов) сохраняются, отмена — нет
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sharedLoadTimeout)
+		defer cancel()
+		return load(loadCtx)
+	})
+
+	select {
+	case result := <-results:
+		return result.Val, result.Err
+	case <-ctx.Done():
+		// Загрузка продолжается для остальных ожидающих и сохранится в кэш
+		return nil, ctx.Err()
+	}
+}
+
+// lookup ищет значение в памяти, а затем в постоянном хранилище.
+// Найденное в хранилище значение поднимается в память.
+func lookup[T any](ctx context.Context, c *CachedSteamAPI, key string) (T, bool) {
+	var zero T
+
+	if value, ok := c.memory.Get(key, c.clock.Now()); ok {
+		return value.(T), true
+	}
+
+	if c.store == nil {
+		return zero, false
+	}
+
+	raw, expiresAt, ok, err := c.store.Get(ctx, key)
+	if err != nil {
+		c.logger.Error(ctx, "Ошибка чтения постоянного кэша", err, "key", key)
+		return zero, false
+	}
+	if !ok {
+		return zero, false
+	}
+
+	var value T
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return zero, false
+	}
+
+	c.memory.Set(key, value, expiresAt)
+	return value, true
+}
+
+// save сохраняет значение в память и в постоянное хранилище
+func (c *CachedSteamAPI) save(ctx context.Context, key string, value any, ttl time.Duration) {
+	expiresAt := c.clock.Now().Add(ttl)
+	c.memory.Set(key, value, expiresAt)
+
+	if c.store == nil {
+		return
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		c.logger.Error(ctx, "Ошибка сериализации кэша", err, "key", key)
+		return
+	}
+
+	// Кэш — не критичная часть: ошибка записи не должна ломать ответ пользователю
+	if err := c.store.Set(ctx, key, raw, expiresAt); err != nil {
+		c.logger.Error(ctx, "Ошибка записи постоянного кэша", err, "key", key)
+	}
+}
+
+// appPriceKey возвращает ключ кэша цены приложения в регионе
+func appPriceKey(countryCode string, appID int) string {
+	return fmt.Sprintf("app-price:%s:%d", countryCode, appID)
+}
+
+// Компиляторная проверка реализации интерфейса.
+var _ interfaces.SteamAPI = (*CachedSteamAPI)(nil)
@@ -0,0 +1,188 @@
+package adapters
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	// CBRDailyURL — ежедневные курсы Центрального банка России
+	CBRDailyURL = "https://www.cbr.ru/scripts/XML_daily.asp"
+	// ECBDailyURL — ежедневные курсы Европейского центрального банка
+	ECBDailyURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+)
+
+// CBRRatesAPI загружает курсы валют к рублю из XML ЦБ РФ.
+type CBRRatesAPI struct {
+	url    string
+	client *http.Client
+}
+
+func NewCBRRatesAPI(url string, timeout time.Duration) *CBRRatesAPI {
+	return &CBRRatesAPI{
+		url: url,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// cbrValCurs — корневой элемент ответа ЦБ.
+// XML: <ValCurs Date="17.10.2026"><Valute><CharCode>USD</CharCode><Nominal>1</Nominal><Value>81,1234</Value></Valute>...</ValCurs>
+type cbrValCurs struct {
+	Date    string `xml:"Date,attr"`
+	Valutes []struct {
+		CharCode string `xml:"CharCode"`
+		Nominal  int    `xml:"Nominal"`
+		Value    string `xml:"Value"` // десятичный разделитель — запятая
+	} `xml:"Valute"`
+}
+
+// GetRates реализует interfaces.CurrencyRatesProvider.
+func (a *CBRRatesAPI) GetRates(ctx context.Context) (*entities.CurrencyRates, error) {
+	var result cbrValCurs
+	if err := fetchXML(ctx, a.client, a.url, &result); err != nil {
+		return nil, err
+	}
+
+	date, err := time.Parse("02.01.2006", result.Date)
+	if err != nil {
+		return nil, fmt.Errorf("некорректная дата курсов ЦБ %q: %w", result.Date, err)
+	}
+
+	rates := &entities.CurrencyRates{
+		Base:   "RUB",
+		Source: "ЦБ РФ",
+		Date:   date,
+		Rates:  make(map[string]float64, len(result.Valutes)),
+	}
+	for _, valute := range result.Valutes {
+		value, err := strconv.ParseFloat(strings.Replace(valute.Value, ",", ".", 1), 64)
+		if err != nil || valute.Nominal <= 0 {
+			continue
+		}
+		// Курс указан за Nominal единиц (например, 100 KZT)
+		rates.Rates[valute.CharCode] = value / float64(valute.Nominal)
+	}
+
+	if len(rates.Rates) == 0 {
+		return nil, errors.New("ЦБ вернул пустой список курсов")
+	}
+
+	return rates, nil
+}
+
+// ECBRatesAPI загружает курсы валют к евро из XML ECB.
+// ECB не публикует курс рубля, поэтому конвертация в рубли с этим источником
+// работает только вместе со статической таблицей курсов.
+type ECBRatesAPI struct {
+	url    string
+	client *http.Client
+}
+
+func NewECBRatesAPI(url string, timeout time.Duration) *ECBRatesAPI {
+	return &ECBRatesAPI{
+		url: url,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// ecbEnvelope — корневой элемент ответа ECB.
+// XML: <Envelope><Cube><Cube time="2026-10-16"><Cube currency="USD" rate="1.0812"/>...</Cube></Cube></Envelope>
+type ecbEnvelope struct {
+	Cube struct {
+		Cube struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"` // сколько единиц валюты стоит 1 EUR
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// GetRates реализует interfaces.CurrencyRatesProvider.
+func (a *ECBRatesAPI) GetRates(ctx context.Context) (*entities.CurrencyRates, error) {
+	var result ecbEnvelope
+	if err := fetchXML(ctx, a.client, a.url, &result); err != nil {
+		return nil, err
+	}
+
+	date, err := time.Parse("2006-01-02", result.Cube.Cube.Time)
+	if err != nil {
+		return nil, fmt.Errorf("некорректная дата курсов ECB %q: %w", result.Cube.Cube.Time, err)
+	}
+
+	rates := &entities.CurrencyRates{
+		Base:   "EUR",
+		Source: "ECB",
+		Date:   date,
+		Rates:  make(map[string]float64, len(result.Cube.Cube.Rates)),
+	}
+	for _, rate := range result.Cube.Cube.Rates {
+		value, err := strconv.ParseFloat(rate.Rate, 64)
+		if err != nil || value <= 0 {
+			continue
+		}
+		// Переворачиваем курс: нам нужно, сколько евро стоит 1 единица валюты
+		rates.Rates[rate.Currency] = 1 / value
+	}
+
+	if len(rates.Rates) == 0 {
+		return nil, errors.New("ECB вернул пустой список курсов")
+	}
+
+	return rates, nil
+}
+
+// fetchXML выполняет GET запрос и декодирует XML ответ в out.
+// Поддерживает кодировку windows-1251, в которой отвечает ЦБ РФ.
+func fetchXML(ctx context.Context, client *http.Client, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("не удалось создать запрос: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP запрос не удался: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("неожиданный статус %d", resp.StatusCode)
+	}
+
+	decoder := xml.NewDecoder(resp.Body)
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		if strings.EqualFold(charset, "windows-1251") {
+			return charmap.Windows1251.NewDecoder().Reader(input), nil
+		}
+		return nil, fmt.Errorf("неподдерживаемая кодировка %q", charset)
+	}
+
+	if err := decoder.Decode(out); err != nil {
+		return fmt.Errorf("не удалось декодировать XML: %w", err)
+	}
+
+	return nil
+}
+
+// Компиляторная проверка реализации интерфейса.
+var (
+	_ interfaces.CurrencyRatesProvider = (*CBRRatesAPI)(nil)
+	_ interfaces.CurrencyRatesProvider = (*ECBRatesAPI)(nil)
+)
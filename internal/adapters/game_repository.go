@@ -0,0 +1,145 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresGameRepository хранит отслеживаемые игры в PostgreSQL (таблица tracked_games).
+type PostgresGameRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresGameRepository(pool *pgxpool.Pool) *PostgresGameRepository {
+	return &PostgresGameRepository{
+		pool: pool,
+	}
+}
+
+// SaveTrackedGame реализует interfaces.GameRepository.
+func (r *PostgresGameRepository) SaveTrackedGame(ctx context.Context, game *entities.TrackedGame) error {
+	// ON CONFLICT DO NOTHING не возвращает строк, если запись уже есть —
+	// так мы отличаем дубликат от успешной вставки
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO tracked_games (game_id, game_name, user_chat_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (game_id, user_chat_id) DO NOTHING
+		RETURNING id, created_at`,
+		game.GameID, game.GameName, game.UserChatID,
+	).Scan(&game.ID, &game.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return interfaces.ErrGameAlreadyTracked
+	}
+	if err != nil {
+		return fmt.Errorf("не удалось сохранить игру: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteTrackedGame реализует interfaces.GameRepository.
+func (r *PostgresGameRepository) DeleteTrackedGame(ctx context.Context, userChatID int64, gameID int64) (bool, error) {
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM tracked_games WHERE user_chat_id = $1 AND game_id = $2`,
+		userChatID, gameID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("не удалось удалить игру: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetTrackedGamesByUser реализует interfaces.GameRepository.
+func (r *PostgresGameRepository) GetTrackedGamesByUser(ctx context.Context, userChatID int64) ([]entities.TrackedGame, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, game_id, game_name, user_chat_id, created_at, last_checked
+		FROM tracked_games
+		WHERE user_chat_id = $1
+		ORDER BY created_at`,
+		userChatID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить список игр: %w", err)
+	}
+
+	return collectTrackedGames(rows)
+}
+
+// GetGamesDueForCheck реализует interfaces.GameRepository.
+func (r *PostgresGameRepository) GetGamesDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]entities.TrackedGame, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, game_id, game_name, user_chat_id, created_at, last_checked
+		FROM tracked_games
+		WHERE last_checked IS NULL OR last_checked < $1
+		ORDER BY last_checked NULLS FIRST
+		LIMIT $2`,
+		checkedBefore, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить игры для проверки: %w", err)
+	}
+
+	return collectTrackedGames(rows)
+}
+
+// GetSubscribers реализует interfaces.GameRepository.
+func (r *PostgresGameRepository) GetSubscribers(ctx context.Context, gameID int64) ([]int64, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT user_chat_id FROM tracked_games WHERE game_id = $1`,
+		gameID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить подписчиков игры: %w", err)
+	}
+
+	chatIDs, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать подписчиков игры: %w", err)
+	}
+
+	return chatIDs, nil
+}
+
+// MarkGameChecked реализует interfaces.GameRepository.
+func (r *PostgresGameRepository) MarkGameChecked(ctx context.Context, gameID int64, checkedAt time.Time) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE tracked_games SET last_checked = $2 WHERE game_id = $1`,
+		gameID, checkedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("не удалось обновить время проверки игры: %w", err)
+	}
+
+	return nil
+}
+
+// collectTrackedGames читает строки tracked_games в сущности
+func collectTrackedGames(rows pgx.Rows) ([]entities.TrackedGame, error) {
+	games, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entities.TrackedGame, error) {
+		var (
+			game        entities.TrackedGame
+			lastChecked *time.Time // last_checked может быть NULL
+		)
+		err := row.Scan(&game.ID, &game.GameID, &game.GameName, &game.UserChatID, &game.CreatedAt, &lastChecked)
+		if lastChecked != nil {
+			game.LastChecked = *lastChecked
+		}
+		return game, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать игры: %w", err)
+	}
+
+	return games, nil
+}
+
+// Компиляторная проверка реализации интерфейса.
+var _ interfaces.GameRepository = (*PostgresGameRepository)(nil)
@@ -0,0 +1,61 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+	"github.com/MaximVod/steambotgo/internal/metrics"
+)
+
+// InstrumentedAiAPI учитывает запросы к AI в метриках: число, длительность и результат
+// по операциям (исправление названия и подбор игр).
+type InstrumentedAiAPI struct {
+	next    interfaces.AiAPI
+	clock   interfaces.Clock
+	metrics *metrics.Metrics
+}
+
+func NewInstrumentedAiAPI(next interfaces.AiAPI, clock interfaces.Clock, metrics *metrics.Metrics) *InstrumentedAiAPI {
+	return &InstrumentedAiAPI{
+		next:    next,
+		clock:   clock,
+		metrics: metrics,
+	}
+}
+
+// SearchGamesByUserQuery реализует interfaces.AiAPI.
+func (a *InstrumentedAiAPI) SearchGamesByUserQuery(ctx context.Context, query string) ([]entities.AICandidate, error) {
+	start := a.clock.Now()
+	candidates, err := a.next.SearchGamesByUserQuery(ctx, query)
+	a.observe(metrics.AIOperationCorrectTitle, start, err)
+
+	return candidates, err
+}
+
+// ParseDiscoveryQuery реализует interfaces.AiAPI.
+func (a *InstrumentedAiAPI) ParseDiscoveryQuery(ctx context.Context, request string) (*entities.DiscoveryFilters, error) {
+	start := a.clock.Now()
+	filters, err := a.next.ParseDiscoveryQuery(ctx, request)
+	a.observe(metrics.AIOperationDiscover, start, err)
+
+	return filters, err
+}
+
+// observe учитывает запрос к AI, начатый в start
+func (a *InstrumentedAiAPI) observe(operation string, start time.Time, err error) {
+	var budgetErr *interfaces.AIBudgetError
+	status := metrics.StatusOK
+	switch {
+	case errors.As(err, &budgetErr):
+		status = metrics.StatusBudgetExceeded
+	case err != nil:
+		status = metrics.StatusError
+	}
+	a.metrics.ObserveAIRequest(operation, status, a.clock.Now().Sub(start))
+}
+
+// Компиляторная проверка реализации интерфейса.
+var _ interfaces.AiAPI = (*InstrumentedAiAPI)(nil)
@@ -0,0 +1,103 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresPriceSnapshotRepository хранит историю цен в PostgreSQL (таблица price_snapshots).
+type PostgresPriceSnapshotRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresPriceSnapshotRepository(pool *pgxpool.Pool) *PostgresPriceSnapshotRepository {
+	return &PostgresPriceSnapshotRepository{
+		pool: pool,
+	}
+}
+
+// SaveSnapshot реализует interfaces.PriceSnapshotRepository.
+func (r *PostgresPriceSnapshotRepository) SaveSnapshot(ctx context.Context, snapshot *entities.PriceSnapshot) error {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO price_snapshots (game_id, country_code, price, currency, discount, checked_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		snapshot.GameID, snapshot.CountryCode, snapshot.Price, snapshot.Currency, snapshot.Discount, snapshot.CheckedAt,
+	).Scan(&snapshot.ID)
+	if err != nil {
+		return fmt.Errorf("не удалось сохранить снимок цены: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestSnapshot реализует interfaces.PriceSnapshotRepository.
+func (r *PostgresPriceSnapshotRepository) GetLatestSnapshot(ctx context.Context, gameID int64, countryCode string) (*entities.PriceSnapshot, error) {
+	var snapshot entities.PriceSnapshot
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, game_id, country_code, price, currency, COALESCE(discount, 0), checked_at
+		FROM price_snapshots
+		WHERE game_id = $1 AND country_code = $2
+		ORDER BY checked_at DESC
+		LIMIT 1`,
+		gameID, countryCode,
+	).Scan(
+		&snapshot.ID,
+		&snapshot.GameID,
+		&snapshot.CountryCode,
+		&snapshot.Price,
+		&snapshot.Currency,
+		&snapshot.Discount,
+		&snapshot.CheckedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить последний снимок цены: %w", err)
+	}
+
+	return &snapshot, nil
+}
+
+// GetSnapshots реализует interfaces.PriceSnapshotRepository.
+// Запрос использует индекс idx_price_snapshots_game (game_id, checked_at).
+func (r *PostgresPriceSnapshotRepository) GetSnapshots(ctx context.Context, gameID int64) ([]entities.PriceSnapshot, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, game_id, country_code, price, currency, COALESCE(discount, 0), checked_at
+		FROM price_snapshots
+		WHERE game_id = $1
+		ORDER BY checked_at ASC`,
+		gameID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить историю цен: %w", err)
+	}
+	snapshots, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entities.PriceSnapshot, error) {
+		var snapshot entities.PriceSnapshot
+		err := row.Scan(
+			&snapshot.ID,
+			&snapshot.GameID,
+			&snapshot.CountryCode,
+			&snapshot.Price,
+			&snapshot.Currency,
+			&snapshot.Discount,
+			&snapshot.CheckedAt,
+		)
+		return snapshot, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать историю цен: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// Компиляторная проверка реализации интерфейса.
+var _ interfaces.PriceSnapshotRepository = (*PostgresPriceSnapshotRepository)(nil)
@@ -0,0 +1,57 @@
+package adapters
+
+import (
+	"context"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+	"github.com/MaximVod/steambotgo/internal/ratelimit"
+)
+
+// RateLimitedAiAPI ограничивает число платных запросов к AI для каждого пользователя.
+// Пользователь берется из контекста (ratelimit.WithUser); запросы без пользователя
+// (например, фоновые задачи) не ограничиваются.
+type RateLimitedAiAPI struct {
+	next    interfaces.AiAPI
+	limiter *ratelimit.Limiter
+}
+
+func NewRateLimitedAiAPI(next interfaces.AiAPI, limiter *ratelimit.Limiter) *RateLimitedAiAPI {
+	return &RateLimitedAiAPI{
+		next:    next,
+		limiter: limiter,
+	}
+}
+
+// SearchGamesByUserQuery реализует interfaces.AiAPI.
+// При исчерпанном лимите возвращает *interfaces.AIBudgetError.
+func (a *RateLimitedAiAPI) SearchGamesByUserQuery(ctx context.Context, query string) ([]entities.AICandidate, error) {
+	if err := a.allow(ctx); err != nil {
+		return nil, err
+	}
+
+	return a.next.SearchGamesByUserQuery(ctx, query)
+}
+
+// ParseDiscoveryQuery реализует interfaces.AiAPI.
+// При исчерпанном лимите возвращает *interfaces.AIBudgetError.
+func (a *RateLimitedAiAPI) ParseDiscoveryQuery(ctx context.Context, request string) (*entities.DiscoveryFilters, error) {
+	if err := a.allow(ctx); err != nil {
+		return nil, err
+	}
+
+	return a.next.ParseDiscoveryQuery(ctx, request)
+}
+
+// allow списывает запрос из бюджета пользователя; все операции AI тратят общий бюджет
+func (a *RateLimitedAiAPI) allow(ctx context.Context) error {
+	if userID, ok := ratelimit.UserFromContext(ctx); ok {
+		if allowed, retryAfter := a.limiter.Allow(userID); !allowed {
+			return &interfaces.AIBudgetError{RetryAfter: retryAfter}
+		}
+	}
+	return nil
+}
+
+// Компиляторная проверка реализации интерфейса.
+var _ interfaces.AiAPI = (*RateLimitedAiAPI)(nil)
@@ -0,0 +1,169 @@
+package adapters
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 10 * time.Second
+)
+
+// retryTransport повторяет идемпотентные запросы при временных сбоях:
+// сетевых ошибках, 429 Too Many Requests и 5xx от Steam.
+// Задержка растет экспоненциально со случайным разбросом (jitter),
+// а если сервер прислал Retry-After — используется она.
+// Таймаут ограничивает каждую попытку отдельно, а не все повторы вместе,
+// поэтому общий срок запроса задается контекстом вызывающего.
+type retryTransport struct {
+	next           http.RoundTripper
+	maxRetries     int
+	attemptTimeout time.Duration // 0 — без ограничения на попытку
+	baseDelay      time.Duration
+	maxDelay       time.Duration
+}
+
+func newRetryTransport(next http.RoundTripper, maxRetries int, attemptTimeout time.Duration) *retryTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &retryTransport{
+		next:           next,
+		maxRetries:     maxRetries,
+		attemptTimeout: attemptTimeout,
+		baseDelay:      retryBaseDelay,
+		maxDelay:       retryMaxDelay,
+	}
+}
+
+// RoundTrip реализует http.RoundTripper.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Повторять можно только запросы без побочных эффектов
+	if !isIdempotent(req) {
+		return t.attempt(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.attempt(req)
+
+		// Если контекст отменен, повторять бессмысленно
+		if req.Context().Err() != nil || attempt >= t.maxRetries || !isRetryable(resp, err) {
+			return resp, err
+		}
+
+		// Если ожидание не укладывается в срок запроса, отдаем последний ответ сразу,
+		// а не ждем, пока истечет контекст: 429 превратится в ErrRateLimited
+		delay := t.backoff(attempt, resp)
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) <= delay {
+			return resp, err
+		}
+
+		if resp != nil {
+			// Дочитываем тело, чтобы соединение вернулось в пул
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt выполняет одну попытку с собственным таймаутом.
+// Контекст попытки отменяется при закрытии тела ответа, поэтому тело можно дочитать после возврата.
+func (t *retryTransport) attempt(req *http.Request) (*http.Response, error) {
+	if t.attemptTimeout <= 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.attemptTimeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose отменяет контекст попытки, когда тело ответа закрыто
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// backoff вычисляет задержку перед следующей попыткой
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(delay, t.maxDelay)
+		}
+	}
+
+	// Full jitter: случайная задержка от 0 до base * 2^attempt
+	ceiling := min(t.baseDelay<<attempt, t.maxDelay)
+	return time.Duration(rand.Int64N(int64(ceiling) + 1))
+}
+
+// isIdempotent проверяет, можно ли безопасно повторить запрос
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return req.Body == nil || req.Body == http.NoBody
+	default:
+		return false
+	}
+}
+
+// isRetryable проверяет, является ли сбой временным
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter разбирает заголовок Retry-After: число секунд или HTTP-дату
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,110 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+)
+
+// SteamAppListURL — список всех приложений Steam (ISteamApps/GetAppList)
+const SteamAppListURL = "https://api.steampowered.com/ISteamApps/GetAppList/v2/"
+
+// steamAppList — ответ GetAppList.
+// JSON: {"applist":{"apps":[{"appid":10,"name":"Counter-Strike"},...]}}
+type steamAppList struct {
+	AppList struct {
+		Apps []struct {
+			AppID int    `json:"appid"`
+			Name  string `json:"name"`
+		} `json:"apps"`
+	} `json:"applist"`
+}
+
+// SteamAppListAPI загружает список приложений из Steam Web API.
+type SteamAppListAPI struct {
+	url    string
+	client *http.Client
+}
+
+func NewSteamAppListAPI(url string, timeout time.Duration) *SteamAppListAPI {
+	return &SteamAppListAPI{
+		url: url,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// GetApps реализует interfaces.AppCatalogProvider.
+func (a *SteamAppListAPI) GetApps(ctx context.Context) ([]entities.SteamApp, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", a.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось создать запрос: %w", err)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP запрос не удался: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("неожиданный статус %d", resp.StatusCode)
+	}
+
+	return decodeAppList(resp.Body)
+}
+
+// FileAppList читает список приложений из сохраненного ответа GetAppList.
+// Подходит, если Steam Web API недоступен с сервера бота.
+type FileAppList struct {
+	path string
+}
+
+func NewFileAppList(path string) *FileAppList {
+	return &FileAppList{
+		path: path,
+	}
+}
+
+// GetApps реализует interfaces.AppCatalogProvider.
+func (f *FileAppList) GetApps(_ context.Context) ([]entities.SteamApp, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть список приложений: %w", err)
+	}
+	defer file.Close()
+
+	return decodeAppList(file)
+}
+
+// decodeAppList разбирает ответ GetAppList
+func decodeAppList(r io.Reader) ([]entities.SteamApp, error) {
+	var result steamAppList
+	if err := json.NewDecoder(r).Decode(&result); err != nil {
+		return nil, fmt.Errorf("не удалось декодировать список приложений: %w", err)
+	}
+	if len(result.AppList.Apps) == 0 {
+		return nil, fmt.Errorf("список приложений пуст")
+	}
+
+	apps := make([]entities.SteamApp, 0, len(result.AppList.Apps))
+	for _, app := range result.AppList.Apps {
+		apps = append(apps, entities.SteamApp{ID: app.AppID, Name: app.Name})
+	}
+
+	return apps, nil
+}
+
+// Компиляторная проверка реализации интерфейса.
+var (
+	_ interfaces.AppCatalogProvider = (*SteamAppListAPI)(nil)
+	_ interfaces.AppCatalogProvider = (*FileAppList)(nil)
+)
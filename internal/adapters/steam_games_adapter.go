@@ -7,10 +7,13 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/MaximVod/steambotgo/internal/entities"
 	"github.com/MaximVod/steambotgo/internal/interfaces"
+	"github.com/MaximVod/steambotgo/internal/metrics"
 )
 
 type SteamGamesAPI struct {
@@ -18,11 +21,15 @@ type SteamGamesAPI struct {
 	client  *http.Client
 }
 
-func NewSteamGamesAPI(baseURL string, timeout time.Duration) *SteamGamesAPI {
+// NewSteamGamesAPI создает клиент Steam.
+// Временные сбои (429, 5xx, сетевые ошибки) повторяются до maxRetries раз.
+// timeout ограничивает каждую попытку, а общий срок запроса задает контекст вызывающего.
+// Каждая попытка учитывается в metrics (nil — метрики отключены).
+func NewSteamGamesAPI(baseURL string, timeout time.Duration, maxRetries int, metrics *metrics.Metrics) *SteamGamesAPI {
 	return &SteamGamesAPI{
 		baseURL: baseURL,
 		client: &http.Client{
-			Timeout: timeout,
+			Transport: newRetryTransport(metrics.SteamTransport(http.DefaultTransport), maxRetries, timeout),
 		},
 	}
 }
@@ -36,31 +43,10 @@ func (f *SteamGamesAPI) SearchGamesByName(ctx context.Context, query string) ([]
 		url.QueryEscape(query), // ← защищает от " ", "&", "%"
 	)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, fmt.Errorf("не удалось создать запрос: %w", err)
-	}
-
-	resp, err := f.client.Do(req)
-	if err != nil {
-		if errors.Is(err, context.Canceled) {
-			return nil, errors.New("поиск отменен")
-		}
-		if errors.Is(err, context.DeadlineExceeded) {
-			return nil, errors.New("поиск превысил время ожидания")
-		}
-		return nil, fmt.Errorf("HTTP запрос не удался: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("неожиданный статус %d", resp.StatusCode)
-	}
-
 	// Парсим ответ
 	var result entities.SteamResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("не удалось декодировать JSON: %w", err)
+	if err := f.getJSON(ctx, endpoint, &result); err != nil {
+		return nil, err
 	}
 
 	return result.Items, nil
@@ -90,31 +76,10 @@ func (f *SteamGamesAPI) GetGamePricesByCountryCode(ctx context.Context, query st
 		countryCode,
 	)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, fmt.Errorf("не удалось создать запрос: %w", err)
-	}
-
-	resp, err := f.client.Do(req)
-	if err != nil {
-		if errors.Is(err, context.Canceled) {
-			return nil, errors.New("поиск отменен")
-		}
-		if errors.Is(err, context.DeadlineExceeded) {
-			return nil, errors.New("поиск превысил время ожидания")
-		}
-		return nil, fmt.Errorf("HTTP запрос не удался: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("неожиданный статус %d", resp.StatusCode)
-	}
-
 	// Парсим ответ
 	var result entities.SteamResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("не удалось декодировать JSON: %w", err)
+	if err := f.getJSON(ctx, endpoint, &result); err != nil {
+		return nil, err
 	}
 
 	if len(result.Items) == 0 {
@@ -136,5 +101,104 @@ func (f *SteamGamesAPI) GetGamePricesByCountryCode(ctx context.Context, query st
 	return &result.Items[0], nil
 }
 
+// maxAppDetailsBatch ограничивает количество App ID в одном запросе appdetails
+const maxAppDetailsBatch = 100
+
+// appDetailsPriceResponse — элемент ответа appdetails с фильтром price_overview.
+// JSON: { "1091500": { "success": true, "data": { "price_overview": {...} } } }
+type appDetailsPriceResponse struct {
+	Success bool `json:"success"`
+	// У бесплатных игр data приходит пустым массивом [], а не объектом,
+	// поэтому разбираем его отдельно
+	Data json.RawMessage `json:"data"`
+}
+
+// GetAppPrices реализует interfaces.SteamAPI.
+func (f *SteamGamesAPI) GetAppPrices(ctx context.Context, appIDs []int, countryCode string) (map[int]*entities.PriceInfo, error) {
+	prices := make(map[int]*entities.PriceInfo, len(appIDs))
+
+	// Фильтр price_overview — единственный, для которого appdetails принимает несколько appids
+	for start := 0; start < len(appIDs); start += maxAppDetailsBatch {
+		end := min(start+maxAppDetailsBatch, len(appIDs))
+
+		ids := make([]string, 0, end-start)
+		for _, id := range appIDs[start:end] {
+			ids = append(ids, strconv.Itoa(id))
+		}
+
+		endpoint := fmt.Sprintf(
+			"%s/api/appdetails?appids=%s&cc=%s&filters=price_overview",
+			f.baseURL,
+			strings.Join(ids, ","),
+			url.QueryEscape(countryCode),
+		)
+
+		var result map[string]appDetailsPriceResponse
+		if err := f.getJSON(ctx, endpoint, &result); err != nil {
+			return nil, err
+		}
+
+		for rawID, details := range result {
+			// success: false — приложение недоступно в этом регионе
+			if !details.Success {
+				continue
+			}
+
+			id, err := strconv.Atoi(rawID)
+			if err != nil {
+				continue
+			}
+
+			var data struct {
+				PriceOverview *entities.PriceInfo `json:"price_overview"`
+			}
+			// Ошибка означает пустой массив вместо объекта — игра бесплатная
+			if err := json.Unmarshal(details.Data, &data); err != nil {
+				prices[id] = nil
+				continue
+			}
+
+			prices[id] = data.PriceOverview
+		}
+	}
+
+	return prices, nil
+}
+
+// getJSON выполняет GET запрос к Steam и декодирует JSON ответ в out
+func (f *SteamGamesAPI) getJSON(ctx context.Context, endpoint string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("не удалось создать запрос: %w", err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return errors.New("поиск отменен")
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return errors.New("поиск превысил время ожидания")
+		}
+		return fmt.Errorf("%w: HTTP запрос не удался: %v", interfaces.ErrUpstreamUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: статус %d", interfaces.ErrRateLimited, resp.StatusCode)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return fmt.Errorf("%w: статус %d", interfaces.ErrUpstreamUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("неожиданный статус %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("не удалось декодировать JSON: %w", err)
+	}
+
+	return nil
+}
+
 // Компиляторная проверка реализации интерфейса.
 var _ interfaces.SteamAPI = (*SteamGamesAPI)(nil)
@@ -0,0 +1,23 @@
+package adapters
+
+import (
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+)
+
+// SystemClock реализует interfaces.Clock через пакет time.
+type SystemClock struct{}
+
+// Now реализует interfaces.Clock.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After реализует interfaces.Clock.
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Компиляторная проверка реализации интерфейса.
+var _ interfaces.Clock = SystemClock{}
@@ -0,0 +1,62 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresUserSettingsRepository хранит настройки чатов в PostgreSQL (таблица user_settings).
+type PostgresUserSettingsRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresUserSettingsRepository(pool *pgxpool.Pool) *PostgresUserSettingsRepository {
+	return &PostgresUserSettingsRepository{
+		pool: pool,
+	}
+}
+
+// GetSettings реализует interfaces.UserSettingsRepository.
+func (r *PostgresUserSettingsRepository) GetSettings(ctx context.Context, chatID int64) (*entities.UserSettings, error) {
+	settings := &entities.UserSettings{ChatID: chatID}
+	err := r.pool.QueryRow(ctx,
+		`SELECT regions, home_currency, language FROM user_settings WHERE chat_id = $1`,
+		chatID,
+	).Scan(&settings.Regions, &settings.HomeCurrency, &settings.Language)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить настройки: %w", err)
+	}
+
+	return settings, nil
+}
+
+// SaveSettings реализует interfaces.UserSettingsRepository.
+func (r *PostgresUserSettingsRepository) SaveSettings(ctx context.Context, settings *entities.UserSettings) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO user_settings (chat_id, regions, home_currency, language, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (chat_id) DO UPDATE SET
+			regions = EXCLUDED.regions,
+			home_currency = EXCLUDED.home_currency,
+			language = EXCLUDED.language,
+			updated_at = EXCLUDED.updated_at`,
+		settings.ChatID, settings.Regions, settings.HomeCurrency, settings.Language,
+	)
+	if err != nil {
+		return fmt.Errorf("не удалось сохранить настройки: %w", err)
+	}
+
+	return nil
+}
+
+// Компиляторная проверка реализации интерфейса.
+var _ interfaces.UserSettingsRepository = (*PostgresUserSettingsRepository)(nil)
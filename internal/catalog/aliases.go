@@ -0,0 +1,78 @@
+package catalog
+
+import "strings"
+
+// titleAliases — сокращения и русские названия популярных игр.
+// Алиас заменяет начало запроса, остальные слова сохраняются: "гта 5" → "grand theft auto 5",
+// "ведьмак 3" → "witcher 3".
+//
+// Список составлен вручную: сокращения, построенные по всем названиям каталога,
+// совпадают у тысяч малоизвестных приложений и находят не то. Названия, которые
+// транслитерация и нечеткое сравнение находят сами ("сталкер", "фаллаут"), сюда не входят.
+var titleAliases = map[string]string{
+	// Сокращения
+	"gta":    "Grand Theft Auto",
+	"rdr":    "Red Dead Redemption",
+	"cod":    "Call of Duty",
+	"csgo":   "Counter-Strike 2",
+	"cs":     "Counter-Strike",
+	"l4d":    "Left 4 Dead",
+	"hl":     "Half-Life",
+	"tes":    "The Elder Scrolls",
+	"mgs":    "Metal Gear Solid",
+	"ff":     "Final Fantasy",
+	"nfs":    "Need for Speed",
+	"ac":     "Assassin's Creed",
+	"bg":     "Baldur's Gate",
+	"ds":     "Dark Souls",
+	"kcd":    "Kingdom Come Deliverance",
+	"mhw":    "Monster Hunter: World",
+	"ror":    "Risk of Rain",
+	"hoi":    "Hearts of Iron",
+	"civ":    "Sid Meier's Civilization",
+	"tw3":    "The Witcher 3",
+	"cp2077": "Cyberpunk 2077",
+
+	// Русские названия, которые не получаются транслитерацией
+	"ведьмак":          "The Witcher",
+	"киберпанк":        "Cyberpunk 2077",
+	"кс":               "Counter-Strike",
+	"контра":           "Counter-Strike",
+	"ассасин":          "Assassin's Creed",
+	"ассасин крид":     "Assassin's Creed",
+	"готика":           "Gothic",
+	"герои":            "Heroes of Might and Magic",
+	"цивилизация":      "Sid Meier's Civilization",
+	"скайрим":          "The Elder Scrolls V: Skyrim",
+	"дарк соулс":       "Dark Souls",
+	"темные души":      "Dark Souls",
+	"халф лайф":        "Half-Life",
+	"балдурс гейт":     "Baldur's Gate",
+	"врата балдура":    "Baldur's Gate",
+	"обитель зла":      "Resident Evil",
+	"резидент ивел":    "Resident Evil",
+	"ред дед редемпшн": "Red Dead Redemption",
+}
+
+// aliasIndex — алиасы по нормализованному ключу без пробелов ("gta 5" и "gta5" — один ключ)
+var aliasIndex = buildAliasIndex(titleAliases)
+
+func buildAliasIndex(aliases map[string]string) map[string]string {
+	index := make(map[string]string, len(aliases))
+	for alias, title := range aliases {
+		index[strings.ReplaceAll(normalize(alias), " ", "")] = normalize(title)
+	}
+	return index
+}
+
+// expandAliases заменяет самый длинный алиас в начале нормализованного запроса
+func expandAliases(query string) string {
+	words := strings.Fields(query)
+	for n := len(words); n > 0; n-- {
+		// Слова склеиваются, потому что normalize разделяет буквы и цифры: "l4d2" → "l 4 d 2"
+		if title, ok := aliasIndex[strings.Join(words[:n], "")]; ok {
+			return strings.Join(append([]string{title}, words[n:]...), " ")
+		}
+	}
+	return query
+}
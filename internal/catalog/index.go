@@ -0,0 +1,230 @@
+// Package catalog ищет игры по локальному списку приложений Steam без запросов к Steam и AI.
+// Поиск терпим к опечаткам (триграммы и расстояние Левенштейна), понимает кириллицу
+// (транслитерация) и распространенные сокращения ("gta5", "rdr2").
+package catalog
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+)
+
+const (
+	// MinScore — минимальная похожесть названия на запрос (от 0 до 1), чтобы считать его найденным
+	MinScore = 0.75
+	// minQueryLength — более короткие запросы совпадают с слишком многими названиями
+	minQueryLength = 3
+	// maxCandidates — сколько названий с наибольшим числом общих триграмм сравнивается подробно
+	maxCandidates = 500
+	// minTrigramShare — доля триграмм запроса, которая должна встретиться в названии-кандидате
+	minTrigramShare = 0.5
+	// nonGamePenalty — множитель похожести для саундтреков, демо и инструментов
+	nonGamePenalty = 0.9
+)
+
+// nonGameWords — слова в названиях приложений, которые не являются самой игрой
+var nonGameWords = map[string]bool{
+	"soundtrack": true, "ost": true, "demo": true, "playtest": true, "test": true,
+	"beta": true, "sdk": true, "modkit": true, "redmod": true, "editor": true,
+	"tool": true, "tools": true, "server": true, "dedicated": true, "artbook": true,
+	"wallpaper": true, "wallpapers": true, "trailer": true, "benchmark": true,
+}
+
+// Index — неизменяемый индекс названий приложений Steam.
+// Безопасен для одновременного использования из нескольких горутин.
+type Index struct {
+	apps     []entities.SteamApp
+	names    []string           // нормализованные названия, индекс совпадает с apps
+	trigrams map[string][]int32 // триграмма → номера названий, в которых она встречается
+}
+
+// NewIndex строит индекс. Приложения без названия пропускаются.
+func NewIndex(apps []entities.SteamApp) *Index {
+	index := &Index{
+		apps:     make([]entities.SteamApp, 0, len(apps)),
+		names:    make([]string, 0, len(apps)),
+		trigrams: make(map[string][]int32),
+	}
+
+	for _, app := range apps {
+		name := normalize(app.Name)
+		if name == "" {
+			continue
+		}
+
+		id := int32(len(index.apps))
+		index.apps = append(index.apps, app)
+		index.names = append(index.names, name)
+		for _, trigram := range trigrams(name) {
+			index.trigrams[trigram] = append(index.trigrams[trigram], id)
+		}
+	}
+
+	return index
+}
+
+// Len возвращает число приложений в индексе
+func (idx *Index) Len() int {
+	return len(idx.apps)
+}
+
+// Match — найденное приложение и похожесть его названия на запрос (от MinScore до 1)
+type Match struct {
+	App   entities.SteamApp
+	Score float64
+}
+
+// Search возвращает до limit приложений, названия которых похожи на запрос,
+// по убыванию похожести. При равной похожести выше более короткое название
+// (игра, а не ее DLC), затем меньший App ID (раньше выпущенное приложение).
+func (idx *Index) Search(query string, limit int) []Match {
+	normalized := expandAliases(normalize(query))
+	if len([]rune(normalized)) < minQueryLength {
+		return nil
+	}
+
+	type scored struct {
+		id    int32
+		score float64
+	}
+	var found []scored
+	for _, candidate := range idx.candidates(normalized) {
+		if score := titleScore(normalized, idx.names[candidate]); score >= MinScore {
+			found = append(found, scored{id: candidate, score: score})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].score != found[j].score {
+			return found[i].score > found[j].score
+		}
+		return idx.better(found[i].id, found[j].id)
+	})
+
+	matches := make([]Match, 0, min(limit, len(found)))
+	for _, f := range found[:min(limit, len(found))] {
+		matches = append(matches, Match{App: idx.apps[f.id], Score: f.score})
+	}
+	return matches
+}
+
+// better сравнивает названия с одинаковой похожестью
+func (idx *Index) better(a, b int32) bool {
+	if len(idx.names[a]) != len(idx.names[b]) {
+		return len(idx.names[a]) < len(idx.names[b])
+	}
+	return idx.apps[a].ID < idx.apps[b].ID
+}
+
+// candidates возвращает названия, в которых встречается хотя бы minTrigramShare триграмм запроса,
+// — не больше maxCandidates с наибольшим числом общих триграмм
+func (idx *Index) candidates(query string) []int32 {
+	queryTrigrams := trigrams(query)
+	shared := make(map[int32]int)
+	for _, trigram := range queryTrigrams {
+		for _, id := range idx.trigrams[trigram] {
+			shared[id]++
+		}
+	}
+
+	minShared := max(1, int(float64(len(queryTrigrams))*minTrigramShare))
+	result := make([]int32, 0, len(shared))
+	for id, count := range shared {
+		if count >= minShared {
+			result = append(result, id)
+		}
+	}
+
+	if len(result) > maxCandidates {
+		sort.Slice(result, func(i, j int) bool {
+			if shared[result[i]] != shared[result[j]] {
+				return shared[result[i]] > shared[result[j]]
+			}
+			return result[i] < result[j]
+		})
+		result = result[:maxCandidates]
+	}
+
+	return result
+}
+
+// titleScore оценивает похожесть нормализованного названия на нормализованный запрос.
+// Кроме названия целиком сравнивается его начало из стольких же слов, сколько в запросе:
+// "witcher 3" похож на "witcher 3 wild hunt". Совпадение по началу ценится меньше,
+// чем по названию целиком, и тем меньше, чем длиннее остаток названия.
+func titleScore(query, name string) float64 {
+	score := similarity(query, name)
+
+	queryWords := len(strings.Fields(query))
+	nameWords := strings.Fields(name)
+	if len(nameWords) > queryWords {
+		prefix := strings.Join(nameWords[:queryWords], " ")
+		lengthRatio := float64(len(prefix)) / float64(len(name))
+		score = max(score, similarity(query, prefix)*(0.8+0.2*lengthRatio))
+	}
+
+	// Саундтреки, демо и инструменты в каталоге идут наравне с играми, а данных
+	// о популярности в нем нет: такие названия уступают играм, если их не искали явно
+	queryWordSet := make(map[string]bool, queryWords)
+	for _, word := range strings.Fields(query) {
+		queryWordSet[word] = true
+	}
+	for _, word := range nameWords {
+		if nonGameWords[word] && !queryWordSet[word] {
+			return score * nonGamePenalty
+		}
+	}
+
+	return score
+}
+
+// trigrams возвращает уникальные триграммы строки; начало и конец отмечаются пробелами,
+// чтобы короткие слова тоже давали триграммы
+func trigrams(s string) []string {
+	runes := []rune(" " + s + " ")
+	seen := make(map[string]bool, len(runes))
+	result := make([]string, 0, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		trigram := string(runes[i : i+3])
+		if !seen[trigram] {
+			seen[trigram] = true
+			result = append(result, trigram)
+		}
+	}
+	return result
+}
+
+// similarity возвращает похожесть строк от 0 до 1 по расстоянию Левенштейна
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein возвращает минимальное число вставок, удалений и замен символов,
+// превращающих a в b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,102 @@
+package catalog
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// cyrillicToLatin — транслитерация кириллицы: "скайрим" → "skayrim", "портал" → "portal".
+// Правила подобраны под написание названий игр, а не под стандарт ГОСТ:
+// й → y и х → h чаще совпадают с оригиналом ("хитман" → "hitman").
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sch", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// romanNumerals — римские номера частей: "Grand Theft Auto V" и "gta 5" должны совпадать.
+// "i" не заменяется: это чаще слово, чем номер.
+var romanNumerals = map[string]string{
+	"ii": "2", "iii": "3", "iv": "4", "v": "5", "vi": "6", "vii": "7",
+	"viii": "8", "ix": "9", "x": "10", "xi": "11", "xii": "12", "xiii": "13",
+}
+
+// stopWords не влияют на поиск: "The Witcher" и "witcher" — одна игра
+var stopWords = map[string]bool{
+	"the": true,
+}
+
+// normalize приводит название к виду для сравнения: нижний регистр, латиница,
+// слова через один пробел, числа отдельными словами, римские номера — арабскими.
+// "The Witcher® 3: Wild Hunt" → "witcher 3 wild hunt", "ГТА5" → "gta 5".
+func normalize(title string) string {
+	var b strings.Builder
+	b.Grow(len(title))
+
+	// prev — класс предыдущего символа: буквы и цифры разделяются пробелом ("rdr2" → "rdr 2")
+	const (
+		classSpace = iota
+		classLetter
+		classDigit
+	)
+	prev := classSpace
+	write := func(s string, class int) {
+		if prev != classSpace && class != classSpace && prev != class {
+			b.WriteByte(' ')
+		}
+		b.WriteString(s)
+		prev = class
+	}
+
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r == '\'' || r == '’' || r == '`' || r == '.':
+			// "Baldur's Gate" → "baldurs gate", "S.T.A.L.K.E.R." → "stalker"
+		case r == '&':
+			write(" and ", classSpace)
+		case cyrillicToLatin[r] != "":
+			write(cyrillicToLatin[r], classLetter)
+		case r == 'ъ' || r == 'ь':
+		case isASCIILetter(r):
+			write(string(r), classLetter)
+		case r >= '0' && r <= '9':
+			write(string(r), classDigit)
+		case isASCIILetter(baseLetter(r)):
+			// "Pokémon" → "pokemon"
+			write(string(baseLetter(r)), classLetter)
+		default:
+			// Остальные символы (знаки препинания, ™, ®, иероглифы) — разделители слов
+			write(" ", classSpace)
+		}
+	}
+
+	words := strings.Fields(b.String())
+	result := words[:0]
+	for _, word := range words {
+		if stopWords[word] {
+			continue
+		}
+		if digits, ok := romanNumerals[word]; ok {
+			word = digits
+		}
+		result = append(result, word)
+	}
+
+	return strings.Join(result, " ")
+}
+
+// isASCIILetter сообщает, является ли r строчной латинской буквой
+func isASCIILetter(r rune) bool {
+	return r >= 'a' && r <= 'z'
+}
+
+// baseLetter возвращает букву без диакритических знаков: é → e, ö → o
+func baseLetter(r rune) rune {
+	for _, base := range norm.NFD.String(string(r)) {
+		return base
+	}
+	return r
+}
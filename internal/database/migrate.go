@@ -2,35 +2,339 @@ package database
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// RunMigrations применяет SQL миграции к базе данных
+// migrationLockKey — ключ advisory lock, чтобы две копии бота не применяли миграции одновременно
+const migrationLockKey = 7_265_110_001
+
+// migrationFileRe — имя файла миграции: 001_create_tables.up.sql / 001_create_tables.down.sql
+var migrationFileRe = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration — одна версия схемы базы данных
 //
 // Что такое миграции?
 // Миграции - это SQL скрипты, которые создают структуру БД (таблицы, индексы).
-// Мы применяем их при старте приложения, чтобы убедиться, что все таблицы созданы.
-func RunMigrations(ctx context.Context, pool *pgxpool.Pool) error {
-	// Получаем путь к файлу миграции
-	// filepath.Join правильно формирует путь для разных ОС (Windows/Linux/macOS)
-	migrationFile := filepath.Join("migrations", "001_create_tables.sql")
-
-	// Читаем содержимое SQL файла
-	sqlContent, err := os.ReadFile(migrationFile)
+// Up переводит схему на эту версию, Down откатывает её обратно.
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string // пустой, если файла .down.sql нет
+	Checksum string // SHA-256 скрипта Up: изменение уже примененной миграции — ошибка
+}
+
+// MigrationStatus — состояние миграции в базе данных
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	Drifted   bool // файл изменился после применения
+}
+
+// Migrator применяет и откатывает миграции, записывая примененные версии в schema_migrations
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration // по возрастанию версии
+}
+
+// appliedMigration — запись из schema_migrations
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// NewMigrator читает миграции из fsys (корень — каталог с файлами *.sql).
+// Номера версий задают порядок применения и должны быть уникальными.
+func NewMigrator(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		pool:       pool,
+		migrations: migrations,
+	}, nil
+}
+
+// loadMigrations находит пары up/down файлов и сортирует их по версии
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить список миграций: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFileRe.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("некорректный номер миграции %s: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("не удалось прочитать файл миграции %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("у миграции %d разные имена: %s и %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+			migration.Checksum = checksum(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	if len(byVersion) == 0 {
+		return nil, fmt.Errorf("не найдено ни одной миграции")
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("у миграции %s нет файла .up.sql", migration)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// checksum возвращает SHA-256 содержимого файла в hex
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// String возвращает имя миграции в виде имени файла без суффикса: 001_create_tables
+func (m Migration) String() string {
+	return fmt.Sprintf("%03d_%s", m.Version, m.Name)
+}
+
+// Up применяет все еще не примененные миграции по возрастанию версии.
+// Каждая миграция выполняется в своей транзакции вместе с записью в schema_migrations.
+// Возвращает примененные миграции.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		state, err := m.verify(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := state[migration.Version]; ok {
+				continue
+			}
+
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, migration.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx,
+					`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+					migration.Version, migration.Name, migration.Checksum,
+				)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("не удалось применить миграцию %s: %w", migration, err)
+			}
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down откатывает steps последних примененных миграций по убыванию версии.
+// Возвращает откаченные миграции.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		state, err := m.verify(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := state[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("у миграции %s нет файла .down.sql", migration)
+			}
+
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, migration.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("не удалось откатить миграцию %s: %w", migration, err)
+			}
+			reverted = append(reverted, migration)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status возвращает все известные миграции с отметкой, применены ли они и не изменились ли файлы
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		state, err := m.appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Migration: migration}
+			if applied, ok := state[migration.Version]; ok {
+				status.Applied = true
+				status.AppliedAt = applied.appliedAt
+				status.Drifted = applied.checksum != migration.Checksum
+			}
+			statuses = append(statuses, status)
+		}
+
+		return nil
+	})
+
+	return statuses, err
+}
+
+// withLock выполняет fn на отдельном соединении под advisory lock.
+// Блокировка сессионная, поэтому все запросы fn должны идти через conn.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
 	if err != nil {
-		return fmt.Errorf("не удалось прочитать файл миграции %s: %w", migrationFile, err)
+		return fmt.Errorf("не удалось получить соединение для миграций: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("не удалось получить блокировку миграций: %w", err)
 	}
+	defer func() {
+		// ctx может быть уже отменен, а блокировку нужно снять в любом случае
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, _ = conn.Exec(unlockCtx, `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+	}()
 
-	// Выполняем SQL команды из файла
-	// Exec выполняет SQL команды, которые не возвращают данные (CREATE, INSERT, UPDATE, DELETE)
-	_, err = pool.Exec(ctx, string(sqlContent))
+	if _, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)`); err != nil {
+		return fmt.Errorf("не удалось создать таблицу schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// verify проверяет, что все примененные миграции известны и их файлы не менялись.
+// Возвращает примененные миграции по версии.
+func (m *Migrator) verify(ctx context.Context, conn *pgxpool.Conn) (map[int64]appliedMigration, error) {
+	state, err := m.appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, migration := range m.migrations {
+		if applied, ok := state[migration.Version]; ok && applied.checksum != migration.Checksum {
+			errs = append(errs, fmt.Errorf("миграция %s изменена после применения: создайте новую миграцию вместо правки старой", migration))
+		}
+	}
+
+	// Версии, которых нет среди файлов: бинарник старее базы данных или файл удален
+	known := make(map[int64]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+	}
+	var unknown []int64
+	for version := range state {
+		if !known[version] {
+			unknown = append(unknown, version)
+		}
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i] < unknown[j] })
+	for _, version := range unknown {
+		errs = append(errs, fmt.Errorf("миграция %03d_%s применена, но её файла нет", version, state[version].name))
+	}
+
+	return state, errors.Join(errs...)
+}
+
+// appliedMigrations читает schema_migrations
+func (m *Migrator) appliedMigrations(ctx context.Context, conn *pgxpool.Conn) (map[int64]appliedMigration, error) {
+	rows, err := conn.Query(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
 	if err != nil {
-		return fmt.Errorf("не удалось применить миграцию: %w", err)
+		return nil, fmt.Errorf("не удалось получить примененные миграции: %w", err)
+	}
+	defer rows.Close()
+
+	state := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var (
+			version int64
+			applied appliedMigration
+		)
+		if err := rows.Scan(&version, &applied.name, &applied.checksum, &applied.appliedAt); err != nil {
+			return nil, fmt.Errorf("не удалось прочитать примененные миграции: %w", err)
+		}
+		state[version] = applied
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось прочитать примененные миграции: %w", err)
 	}
 
-	return nil
+	return state, nil
 }
@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+// CurrencyRates — курсы валют относительно базовой валюты на определенную дату.
+type CurrencyRates struct {
+	Base   string             // базовая валюта (RUB для ЦБ РФ, EUR для ECB)
+	Source string             // источник курсов для пользователя, например "ЦБ РФ"
+	Date   time.Time          // дата, на которую установлены курсы (нулевая для статической таблицы)
+	Rates  map[string]float64 // currency code -> сколько единиц Base стоит 1 единица валюты
+}
+
+// Convert переводит сумму из одной валюты в другую через базовую валюту.
+// Возвращает false, если курса одной из валют нет.
+func (r *CurrencyRates) Convert(amount float64, from, to string) (float64, bool) {
+	fromRate, ok := r.rate(from)
+	if !ok {
+		return 0, false
+	}
+	toRate, ok := r.rate(to)
+	if !ok || toRate == 0 {
+		return 0, false
+	}
+
+	return amount * fromRate / toRate, true
+}
+
+// rate возвращает курс валюты к базовой (для самой базовой валюты — 1)
+func (r *CurrencyRates) rate(currency string) (float64, bool) {
+	if currency == r.Base {
+		return 1, true
+	}
+	rate, ok := r.Rates[currency]
+	return rate, ok && rate > 0
+}
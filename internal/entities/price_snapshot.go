@@ -0,0 +1,82 @@
+package entities
+
+import "time"
+
+// PriceSnapshot — снимок цены игры в одном регионе на момент проверки.
+type PriceSnapshot struct {
+	ID          int64
+	GameID      int64 // Steam App ID
+	CountryCode string
+	Price       int // финальная цена в центах
+	Currency    string
+	Discount    int // процент скидки (0 — скидки нет)
+	CheckedAt   time.Time
+}
+
+// PriceChange — изменение цены в одном регионе между двумя проверками.
+type PriceChange struct {
+	CountryCode string
+	CountryFlag string
+	Currency    string
+	OldPrice    int // в центах
+	NewPrice    int // в центах
+	OldDiscount int
+	NewDiscount int
+}
+
+// PriceDrop — снижение цены отслеживаемой игры, о котором нужно уведомить подписчиков.
+type PriceDrop struct {
+	GameID   int64
+	GameName string
+	Changes  []PriceChange
+}
+
+// PriceVerdict — оценка текущей цены относительно истории цен.
+type PriceVerdict string
+
+const (
+	PriceVerdictNone         PriceVerdict = ""              // обычная цена, ничего примечательного
+	PriceVerdictAllTimeLow   PriceVerdict = "all_time_low"  // цена ниже всех прежних или равна минимуму менявшейся цены
+	PriceVerdictNearLow      PriceVerdict = "near_low"      // цена близка к историческому минимуму
+	PriceVerdictAboveAverage PriceVerdict = "above_average" // цена выше средней, лучше подождать
+)
+
+// PriceStats — исторический минимум цены в регионе и оценка текущей цены.
+type PriceStats struct {
+	LowestPrice  int // в центах
+	LowestAt     time.Time
+	AveragePrice int // в центах
+	Verdict      PriceVerdict
+}
+
+// PriceHistory — история цен игры по регионам.
+type PriceHistory struct {
+	GameID   int64
+	GameName string
+	Regions  []RegionPriceHistory // в порядке регионов из конфигурации
+}
+
+// RegionPriceHistory — снимки цены игры в одном регионе по возрастанию времени проверки.
+// Все снимки в одной валюте.
+type RegionPriceHistory struct {
+	CountryCode string
+	CountryFlag string
+	Currency    string
+	Snapshots   []PriceSnapshot
+}
+
+// Lowest возвращает исторический минимум цены (при равенстве — самый ранний снимок).
+func (h RegionPriceHistory) Lowest() PriceSnapshot {
+	lowest := h.Snapshots[0]
+	for _, snapshot := range h.Snapshots[1:] {
+		if snapshot.Price < lowest.Price {
+			lowest = snapshot
+		}
+	}
+	return lowest
+}
+
+// Latest возвращает последний снимок цены.
+func (h RegionPriceHistory) Latest() PriceSnapshot {
+	return h.Snapshots[len(h.Snapshots)-1]
+}
@@ -0,0 +1,9 @@
+package entities
+
+// Region — регион магазина Steam, в котором бот показывает цены.
+type Region struct {
+	Code     string // код страны для Steam (cc), например "KZ"
+	Name     string // название для пользователя, например "Казахстан"
+	Flag     string // эмодзи флага
+	Currency string // основная валюта магазина региона
+}
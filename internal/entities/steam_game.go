@@ -2,8 +2,8 @@ package entities
 
 import (
 	"encoding/json"
-	"fmt"
 	"strconv"
+	"time"
 )
 
 // SteamResponse — корневой объект ответа.
@@ -70,10 +70,27 @@ type SteamItem struct {
 }
 
 // PriceInfo — информация о цене.
+// Поиск Steam возвращает только валюту и цены, а appdetails (price_overview)
+// дополнительно — процент скидки и отформатированные цены.
 type PriceInfo struct {
-	Currency string `json:"currency"`
-	Initial  int    `json:"initial"` // в центах (999 = $9.99)
-	Final    int    `json:"final"`   // в центах
+	Currency         string `json:"currency"`
+	Initial          int    `json:"initial"`                     // в центах (999 = $9.99)
+	Final            int    `json:"final"`                       // в центах
+	DiscountPercent  int    `json:"discount_percent,omitempty"`  // только в appdetails
+	InitialFormatted string `json:"initial_formatted,omitempty"` // например, "$59.99" (может быть пустым без скидки)
+	FinalFormatted   string `json:"final_formatted,omitempty"`   // например, "$29.99"
+}
+
+// Discount возвращает процент скидки из ответа Steam,
+// а если его нет (поиск) — вычисляет по начальной и финальной цене.
+func (p PriceInfo) Discount() int {
+	if p.DiscountPercent > 0 {
+		return p.DiscountPercent
+	}
+	if p.Initial <= 0 || p.Final >= p.Initial {
+		return 0
+	}
+	return (p.Initial - p.Final) * 100 / p.Initial
 }
 
 // Platforms — поддерживаемые ОС.
@@ -83,69 +100,84 @@ type Platforms struct {
 	Linux   bool `json:"linux"`
 }
 
-// String возвращает человекочитаемое представление игры для Telegram.
-func (s SteamItem) String() string {
-	// Цена
-	price := "бесплатно"
-	if s.Price != nil {
-		// Форматируем как $9.99 (не 999 центов!)
-		price = fmt.Sprintf("%s %.2f", s.Price.Currency, float64(s.Price.Final)/100)
-	}
+// RegionStatus describes the outcome of a price lookup in a region
+type RegionStatus string
 
-	// Платформы (эмодзи)
-	var platforms string
-	if s.Platforms.Windows {
-		platforms += "🖥️"
-	}
-	if s.Platforms.Mac {
-		platforms += "🍎"
-	}
-	if s.Platforms.Linux {
-		platforms += "🐧"
-	}
-	if platforms == "" {
-		platforms = "—"
-	}
-
-	// Metascore (если есть)
-	metascore := ""
-	if !s.Metascore.IsEmpty() {
-		metascore = fmt.Sprintf(" ⭐ %s", s.Metascore.String())
-	}
-
-	// Controller support (если есть)
-	controller := ""
-	if s.ControllerSupport != "" {
-		controller = fmt.Sprintf(" 🎮 %s", s.ControllerSupport)
-	}
-
-	// Формируем строку (Markdown/HTML-friendly)
-	return fmt.Sprintf(
-		"🎮 *%s*\n"+
-			"💰 %s\n"+
-			"📊%s\n"+
-			"💻 %s\n"+
-			"🔗 [Store](https://store.steampowered.com/app/%d/)%s",
-		s.Name,
-		price,
-		metascore,
-		platforms,
-		s.ID,
-		controller,
-	)
-}
+const (
+	RegionStatusOK          RegionStatus = "ok"          // price (or free) received
+	RegionStatusUnavailable RegionStatus = "unavailable" // game is not sold in the region
+	RegionStatusTimeout     RegionStatus = "timeout"     // region lookup exceeded its deadline
+	RegionStatusError       RegionStatus = "error"       // Steam request failed
+)
 
 // RegionalPriceInfo represents price information for a specific region
 type RegionalPriceInfo struct {
-	CountryCode  string
-	CountryFlag  string
-	Item         *SteamItem
-	ConvertedRub float64 // Converted price to rubles if available
+	CountryCode       string
+	CountryName       string
+	CountryFlag       string
+	Status            RegionStatus
+	Err               error       // Lookup error for RegionStatusTimeout / RegionStatusError
+	Item              *SteamItem  // nil unless Status is RegionStatusOK
+	Converted         float64     // Price converted to ConvertedCurrency if available
+	ConvertedCurrency string      // User's home currency (RUB by default)
+	Stats             *PriceStats // Price history in the region; nil without enough snapshots
 }
 
 // MultiRegionPriceData holds pricing information across multiple regions
 type MultiRegionPriceData struct {
-	ID       int
-	GameName string
-	Regions  []*RegionalPriceInfo
+	ID          int
+	GameName    string
+	Regions     []*RegionalPriceInfo
+	RatesSource string           // Source of live currency rates used for conversion (empty for static rates)
+	RatesDate   time.Time        // Date of live currency rates (zero for static rates)
+	Correction  *QueryCorrection // Correction applied to the query; nil if Steam found the query as is
+}
+
+// SteamApp is an entry of the Steam app list (ISteamApps/GetAppList):
+// games, DLC, soundtracks, tools and servers alike
+type SteamApp struct {
+	ID   int
+	Name string
+}
+
+// AICandidate is a game title suggested by AI for a query Steam could not find
+type AICandidate struct {
+	Title      string
+	Confidence float64 // 0..1, as reported by the model
+}
+
+// CorrectionSource tells where a query correction came from
+type CorrectionSource string
+
+const (
+	CorrectionSourceCatalog CorrectionSource = "catalog" // local Steam app catalog
+	CorrectionSourceAI      CorrectionSource = "ai"      // AI title suggestion
+)
+
+// QueryCorrection records which title resolved the user's query after Steam search missed it
+type QueryCorrection struct {
+	Query      string // Original user query
+	Title      string
+	Confidence float64 // 0..1: AI confidence or catalog title similarity
+	Source     CorrectionSource
+}
+
+// DiscoveryFilters is a free-form game request ("co-op roguelike under 500 rubles in Turkey")
+// turned into structured filters by AI
+type DiscoveryFilters struct {
+	Genres            []string // Genres and tags AI picked Titles for (co-op, roguelike); Steam search has no genres, so they are not checked
+	MaxPrice          float64  // Price cap in MaxPriceCurrency; 0 means no cap
+	MaxPriceCurrency  string   // Empty means the store currency of the region
+	CountryCode       string   // Store region; empty means the user's first selected region
+	Platforms         []string // Required platforms: windows, mac, linux
+	ControllerSupport bool     // Only games with controller support
+	Titles            []string // Games matching the request suggested by AI, best first
+}
+
+// DiscoveryResult holds games that passed the discovery filters, priced in Region
+type DiscoveryResult struct {
+	Query   string // Original user request
+	Filters *DiscoveryFilters
+	Region  Region
+	Items   []SteamItem
 }
@@ -0,0 +1,19 @@
+package entities
+
+// UserSettings — настройки чата: какие регионы показывать, в какую валюту конвертировать цены и язык.
+type UserSettings struct {
+	ChatID       int64    // Telegram Chat ID
+	Regions      []string // коды выбранных регионов
+	HomeCurrency string   // валюта для конвертации цен
+	Language     string   // язык сообщений ("ru", "en"); пустой — как в Telegram
+}
+
+// HasRegion проверяет, выбран ли регион
+func (s *UserSettings) HasRegion(code string) bool {
+	for _, region := range s.Regions {
+		if region == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-telegram/bot"
+)
+
+// commandFunc обрабатывает команду; args — текст после команды
+type commandFunc func(ctx context.Context, b *bot.Bot, req *request, args string)
+
+// command — зарегистрированная команда бота
+type command struct {
+	name           string // без "/", в нижнем регистре
+	descriptionKey string // ключ описания в каталоге i18n
+	handler        commandFunc
+}
+
+// commandRouter разбирает команды вида "/find@BotName аргументы"
+// и передает их зарегистрированным обработчикам
+type commandRouter struct {
+	botUsername string // без "@"; пустой — принимаются команды с любым суффиксом
+	order       []string
+	byName      map[string]command
+}
+
+// newCommandRouter создает пустой маршрутизатор команд
+func newCommandRouter() *commandRouter {
+	return &commandRouter{
+		byName: make(map[string]command),
+	}
+}
+
+// Register добавляет команду. Команды показываются в /help и меню Telegram
+// в порядке регистрации; повторная регистрация заменяет обработчик.
+func (r *commandRouter) Register(name, descriptionKey string, handler commandFunc) {
+	cmd := command{
+		name:           strings.ToLower(strings.TrimPrefix(name, "/")),
+		descriptionKey: descriptionKey,
+		handler:        handler,
+	}
+	if _, exists := r.byName[cmd.name]; !exists {
+		r.order = append(r.order, cmd.name)
+	}
+	r.byName[cmd.name] = cmd
+}
+
+// SetBotUsername задает имя бота, чтобы в группах не реагировать
+// на команды, адресованные другим ботам (/find@OtherBot)
+func (r *commandRouter) SetBotUsername(username string) {
+	r.botUsername = strings.TrimPrefix(username, "@")
+}
+
+// Commands возвращает зарегистрированные команды в порядке регистрации
+func (r *commandRouter) Commands() []command {
+	commands := make([]command, 0, len(r.order))
+	for _, name := range r.order {
+		commands = append(commands, r.byName[name])
+	}
+	return commands
+}
+
+// Has проверяет, зарегистрирована ли команда name (результат Parse)
+func (r *commandRouter) Has(name string) bool {
+	_, ok := r.byName[name]
+	return ok
+}
+
+// Parse разбирает текст сообщения на имя команды (без "/" и суффикса бота) и аргументы.
+// Возвращает false, если текст не команда или команда адресована другому боту.
+func (r *commandRouter) Parse(text string) (string, string, bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "/") {
+		return "", "", false
+	}
+
+	// Аргументы могут отделяться пробелом или переводом строки
+	name, args := text, ""
+	if i := strings.IndexAny(text, " \n\t"); i >= 0 {
+		name, args = text[:i], strings.TrimSpace(text[i+1:])
+	}
+
+	name, username, hasUsername := strings.Cut(strings.TrimPrefix(name, "/"), "@")
+	if hasUsername && r.botUsername != "" && !strings.EqualFold(username, r.botUsername) {
+		return "", "", false
+	}
+	if name == "" {
+		return "", "", false
+	}
+
+	return strings.ToLower(name), args, true
+}
+
+// Dispatch вызывает обработчик команды name (результат Parse).
+// Возвращает false, если команда не зарегистрирована.
+func (r *commandRouter) Dispatch(ctx context.Context, b *bot.Bot, req *request, name, args string) bool {
+	cmd, ok := r.byName[name]
+	if !ok {
+		return false
+	}
+
+	cmd.handler(ctx, b, req, args)
+	return true
+}
@@ -1,116 +1,695 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/i18n"
 	"github.com/MaximVod/steambotgo/internal/interfaces"
 	"github.com/MaximVod/steambotgo/internal/logger"
+	"github.com/MaximVod/steambotgo/internal/metrics"
 	"github.com/MaximVod/steambotgo/internal/presenters"
+	"github.com/MaximVod/steambotgo/internal/ratelimit"
 	"github.com/MaximVod/steambotgo/internal/usecases"
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
 )
 
-const (
-	commandFind = "/find"
-)
+// callbackPricePrefix — префикс данных кнопки выбора игры: "price:<appID>"
+const callbackPricePrefix = "price:"
 
 // TelegramHandler обрабатывает сообщения от Telegram
 type TelegramHandler struct {
 	multiRegionService *usecases.MultiRegionPriceService
 	searchService      *usecases.SearchGamesService
+	discoveryService   *usecases.DiscoveryService
+	trackService       *usecases.TrackGamesService
+	historyService     *usecases.PriceHistoryService
+	priceStatsService  *usecases.PriceStatsService
+	settingsService    *usecases.UserSettingsService
+	router             *commandRouter
+	limiter            *ratelimit.Limiter
+	inlineLimiter      *ratelimit.Limiter // отдельный лимит inline-запросов, чтобы набор текста не тратил лимит команд
+	metrics            *metrics.Metrics
 	formatter          *presenters.MessageFormatter
 	logger             logger.Logger
+	maxSearchResults   int
+	inlineCacheTime    time.Duration
 }
 
 // NewTelegramHandler создает новый обработчик Telegram сообщений
 func NewTelegramHandler(
 	steamAPI interfaces.SteamAPI,
 	aiApi interfaces.AiAPI,
+	gameRepo interfaces.GameRepository,
+	snapshotRepo interfaces.PriceSnapshotRepository,
 	formatter *presenters.MessageFormatter,
 	logger logger.Logger,
-	countries map[string]string,
-	currencyRates map[string]float64,
+	countries []entities.Region,
+	currencyRates *usecases.CurrencyRatesService,
+	appCatalog *usecases.AppCatalogService,
+	settingsService *usecases.UserSettingsService,
+	limiter *ratelimit.Limiter,
+	inlineLimiter *ratelimit.Limiter,
+	clock interfaces.Clock,
+	metrics *metrics.Metrics,
+	regionWorkers int,
+	regionTimeout time.Duration,
+	maxSearchResults int,
+	inlineCacheTime time.Duration,
 ) *TelegramHandler {
-	return &TelegramHandler{
-		multiRegionService: usecases.NewMultiRegionPriceService(steamAPI, aiApi, countries, currencyRates),
-		searchService:      usecases.NewSearchGamesService(steamAPI, aiApi),
+	multiRegionService := usecases.NewMultiRegionPriceService(steamAPI, aiApi, appCatalog, countries, currencyRates, regionWorkers, regionTimeout)
+
+	h := &TelegramHandler{
+		router:             newCommandRouter(),
+		limiter:            limiter,
+		inlineLimiter:      inlineLimiter,
+		metrics:            metrics,
+		multiRegionService: multiRegionService,
+		searchService:      usecases.NewSearchGamesService(steamAPI, aiApi, logger),
+		discoveryService:   usecases.NewDiscoveryService(steamAPI, aiApi, currencyRates, countries, regionWorkers, maxSearchResults),
+		trackService:       usecases.NewTrackGamesService(gameRepo, multiRegionService),
+		historyService:     usecases.NewPriceHistoryService(snapshotRepo, gameRepo, multiRegionService, countries),
+		priceStatsService:  usecases.NewPriceStatsService(snapshotRepo, clock),
+		settingsService:    settingsService,
 		formatter:          formatter,
 		logger:             logger,
+		maxSearchResults:   maxSearchResults,
+		inlineCacheTime:    inlineCacheTime,
+	}
+	h.registerCommands()
+
+	return h
+}
+
+// registerCommands регистрирует команды бота в порядке, в котором они показываются в /help
+func (h *TelegramHandler) registerCommands() {
+	h.router.Register("find", "command.find", h.handleFind)
+	h.router.Register("ask", "command.ask", h.handleAsk)
+	h.router.Register("track", "command.track", h.handleTrack)
+	h.router.Register("untrack", "command.untrack", h.handleUntrack)
+	h.router.Register("tracked", "command.tracked", func(ctx context.Context, b *bot.Bot, req *request, _ string) {
+		h.handleTracked(ctx, b, req)
+	})
+	h.router.Register("history", "command.history", h.handleHistory)
+	h.router.Register("settings", "command.settings", func(ctx context.Context, b *bot.Bot, req *request, _ string) {
+		h.handleSettings(ctx, b, req)
+	})
+	h.router.Register("help", "command.help", func(ctx context.Context, b *bot.Bot, req *request, _ string) {
+		h.sendText(ctx, b, req.chatID, h.helpText(req.locale))
+	})
+	h.router.Register("start", "command.start", func(ctx context.Context, b *bot.Bot, req *request, _ string) {
+		h.sendText(ctx, b, req.chatID, req.locale.T("start.greeting")+"\n\n"+h.helpText(req.locale))
+	})
+}
+
+// SetBotUsername задает имя бота для разбора команд вида /find@BotName в группах
+func (h *TelegramHandler) SetBotUsername(username string) {
+	h.router.SetBotUsername(username)
+}
+
+// RegisterBotCommands публикует список команд в меню Telegram (setMyCommands)
+// на языке по умолчанию и отдельно для каждого поддерживаемого языка
+func (h *TelegramHandler) RegisterBotCommands(ctx context.Context, b *bot.Bot) error {
+	for _, locale := range i18n.Supported {
+		params := &bot.SetMyCommandsParams{Commands: h.botCommands(locale)}
+		if locale != i18n.Default {
+			params.LanguageCode = string(locale)
+		}
+
+		if _, err := b.SetMyCommands(ctx, params); err != nil {
+			return fmt.Errorf("не удалось зарегистрировать команды (%s): %w", locale, err)
+		}
 	}
+
+	return nil
 }
 
-// Handle обрабатывает обновление от Telegram
+// botCommands возвращает команды с описаниями для меню Telegram
+func (h *TelegramHandler) botCommands(locale i18n.Locale) []models.BotCommand {
+	var commands []models.BotCommand
+	for _, cmd := range h.router.Commands() {
+		commands = append(commands, models.BotCommand{
+			Command:     cmd.name,
+			Description: locale.T(cmd.descriptionKey),
+		})
+	}
+	return commands
+}
+
+// helpText возвращает список команд с описаниями
+func (h *TelegramHandler) helpText(locale i18n.Locale) string {
+	lines := []string{locale.T("help.title")}
+	for _, cmd := range h.router.Commands() {
+		lines = append(lines, fmt.Sprintf("/%s — %s", cmd.name, locale.T(cmd.descriptionKey)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Handle обрабатывает обновление от Telegram.
+// Пользователь обновления сохраняется в ctx, чтобы лимиты (например, бюджет AI) считались по нему,
+// а ID корреляции — чтобы все записи лога об одном обновлении можно было найти вместе.
 func (h *TelegramHandler) Handle(ctx context.Context, b *bot.Bot, update *models.Update) {
+	ctx = logger.WithCorrelationID(ctx, logger.NewCorrelationID())
+	h.logger.Debug(ctx, "Получено обновление", "update_id", update.ID)
+
+	switch {
+	case update.CallbackQuery != nil:
+		// Нажатие на кнопку выбора игры
+		ctx = ratelimit.WithUser(ctx, update.CallbackQuery.From.ID)
+		h.handleCallback(ctx, b, update.CallbackQuery)
+		return
+	case update.InlineQuery != nil:
+		// Inline-запрос из любого чата: @bot название
+		if update.InlineQuery.From != nil {
+			ctx = ratelimit.WithUser(ctx, update.InlineQuery.From.ID)
+		}
+		h.handleInlineQuery(ctx, b, update.InlineQuery)
+		return
+	case update.ChosenInlineResult != nil:
+		ctx = ratelimit.WithUser(ctx, update.ChosenInlineResult.From.ID)
+		h.handleChosenInlineResult(ctx, b, update.ChosenInlineResult)
+		return
+	}
+
 	// Проверяем, есть ли у обновления сообщение и содержит ли оно текст
 	if update.Message == nil || update.Message.Text == "" {
 		return
 	}
 
-	// Проверяем, начинается ли сообщение с команды /find
-	if !strings.HasPrefix(update.Message.Text, commandFind+" ") && update.Message.Text != commandFind {
+	// Обычный текст без команды не обрабатываем
+	name, args, ok := h.router.Parse(update.Message.Text)
+	if !ok {
+		return
+	}
+
+	// Незнакомые команды не тратят лимит пользователя и не загружают настройки:
+	// в группах они адресованы другим ботам, и бот на них не отвечает
+	if !h.router.Has(name) {
+		h.metrics.ObserveCommand(unknownCommandLabel, metrics.StatusNotFound)
+		if update.Message.Chat.Type == models.ChatTypePrivate {
+			locale := userLocale(nil, update.Message.From)
+			h.sendText(ctx, b, update.Message.Chat.ID, locale.T("command.unknown"))
+		}
+		return
+	}
+
+	req := h.newRequest(ctx, update.Message.Chat.ID, update.Message.From)
+	ctx = ratelimit.WithUser(ctx, req.userID())
+
+	// Каждая команда — несколько запросов к Steam, поэтому частые команды одного пользователя отклоняем
+	if allowed, retryAfter := h.limiter.Allow(req.userID()); !allowed {
+		h.logger.Info(ctx, "Превышен лимит запросов пользователя", "userID", req.userID(), "command", name)
+		h.metrics.ObserveCommand(name, metrics.StatusRateLimited)
+		h.sendText(ctx, b, req.chatID, req.locale.T("ratelimit.slow_down", retryAfterText(req.locale, retryAfter)))
 		return
 	}
 
-	// Извлекаем поисковый запрос после команды /find
-	query := strings.TrimPrefix(update.Message.Text, commandFind+" ")
-	query = strings.TrimSpace(query)
+	h.metrics.ObserveCommand(name, metrics.StatusOK)
+	h.router.Dispatch(ctx, b, req, name, args)
+}
+
+// request — чат, из которого пришло обновление, его настройки и язык ответа
+type request struct {
+	chatID   int64
+	user     *models.User           // автор обновления (может быть nil)
+	settings *entities.UserSettings // nil, если настройки не удалось загрузить
+	locale   i18n.Locale
+}
+
+// unknownCommandLabel — метка метрик для незарегистрированных команд,
+// чтобы текст пользователя не попадал в метки
+const unknownCommandLabel = "unknown"
+
+// newRequest загружает настройки чата и выбирает язык ответа.
+// from — автор обновления, его язык в Telegram используется, если язык не выбран в /settings.
+func (h *TelegramHandler) newRequest(ctx context.Context, chatID int64, from *models.User) *request {
+	settings := h.userSettings(ctx, chatID)
+	return &request{
+		chatID:   chatID,
+		user:     from,
+		settings: settings,
+		locale:   userLocale(settings, from),
+	}
+}
+
+// userID возвращает ID автора обновления, а если он неизвестен — ID чата
+func (r *request) userID() int64 {
+	if r.user != nil {
+		return r.user.ID
+	}
+	return r.chatID
+}
+
+// userLocale возвращает язык из настроек, а если он не выбран — язык пользователя в Telegram
+func userLocale(settings *entities.UserSettings, from *models.User) i18n.Locale {
+	if settings != nil && settings.Language != "" {
+		return i18n.Parse(settings.Language)
+	}
+	if from != nil {
+		return i18n.Parse(from.LanguageCode)
+	}
+	return i18n.Default
+}
+
+// handleFind ищет цены на игру во всех поддерживаемых регионах
+func (h *TelegramHandler) handleFind(ctx context.Context, b *bot.Bot, req *request, query string) {
+	chatID := req.chatID
 
 	// Если запрос пустой (только команда), отправляем сообщение пользователю
 	if query == "" {
-		h.sendMessage(ctx, b, update.Message.Chat.ID, "Пожалуйста, укажите название игры после команды /find")
+		h.sendText(ctx, b, chatID, req.locale.T("find.usage"))
 		return
 	}
 
 	// Валидация запроса
-	if err := h.validateQuery(query); err != nil {
-		h.sendMessage(ctx, b, update.Message.Chat.ID, "❌ "+err.Error())
+	if err := h.validateQuery(req.locale, query); err != nil {
+		h.sendText(ctx, b, chatID, "❌ "+err.Error())
+		return
+	}
+
+	// Если поиск нашел несколько разных игр, просим пользователя выбрать нужную,
+	// чтобы не показать цены на другое издание или саундтрек
+	items, err := h.searchService.FetchGames(ctx, query)
+	if err != nil {
+		h.logger.Error(ctx, "Ошибка поиска игр", err, "query", query)
+		if isSteamUnavailable(err) {
+			h.sendText(ctx, b, chatID, errorMessage(req.locale, err, ""))
+			return
+		}
+	}
+	if needsDisambiguation(items, query) {
+		h.sendGameChoice(ctx, b, req, query, items)
 		return
 	}
 
 	// Пытаемся получить многорегиональные цены
-	prices, err := h.multiRegionService.GetMultiRegionPrices(ctx, query)
+	prices, err := h.multiRegionService.GetMultiRegionPrices(ctx, query, req.settings)
 	if err != nil {
-		h.logger.Error("Ошибка получения многонациональных цен", err, "query", query)
+		h.logger.Error(ctx, "Ошибка получения многонациональных цен", err, "query", query)
+		// Если Steam перегружен, обычный поиск тоже не сработает — сразу сообщаем об этом
+		if isSteamUnavailable(err) || isAIBudgetExceeded(err) {
+			h.sendText(ctx, b, chatID, errorMessage(req.locale, err, ""))
+			return
+		}
 		// Fallback: возвращаемся к обычному поиску
 		items, err := h.searchService.FetchGames(ctx, query)
 		if err != nil {
-			h.logger.Error("Ошибка поиска игр", err, "query", query)
-			h.sendMessage(ctx, b, update.Message.Chat.ID, "Произошла ошибка при поиске игры.")
+			h.logger.Error(ctx, "Ошибка поиска игр", err, "query", query)
+			h.sendText(ctx, b, chatID, errorMessage(req.locale, err, req.locale.T("find.error")))
+			return
+		}
+
+		h.logger.Info(ctx, "Найдено игр", "count", len(items))
+		message := h.formatter.FormatSteamItems(req.locale, items)
+		h.sendMessage(ctx, b, chatID, message)
+		return
+	}
+
+	h.logger.Info(ctx, "Найдены цены для игры", "game", prices.GameName, "regions", len(prices.Regions))
+	for _, region := range prices.Regions {
+		if region.Err != nil {
+			h.logger.Error(ctx, "Ошибка получения цены в регионе", region.Err, "game", prices.GameName, "country", region.CountryCode)
+		}
+	}
+	h.addPriceStats(ctx, prices)
+	message := h.formatter.FormatMultiRegionPrices(req.locale, prices)
+	h.sendMessage(ctx, b, chatID, message)
+}
+
+// handleAsk подбирает игры по описанию в свободной форме:
+// "кооперативный рогалик до 500 рублей в Турции"
+func (h *TelegramHandler) handleAsk(ctx context.Context, b *bot.Bot, req *request, query string) {
+	chatID := req.chatID
+
+	if query == "" {
+		h.sendText(ctx, b, chatID, req.locale.T("ask.usage"))
+		return
+	}
+
+	if err := h.validateQuery(req.locale, query); err != nil {
+		h.sendText(ctx, b, chatID, "❌ "+err.Error())
+		return
+	}
+
+	result, err := h.discoveryService.Discover(ctx, query, req.settings)
+	if err != nil {
+		var regionErr *usecases.UnsupportedRegionError
+		if errors.As(err, &regionErr) {
+			h.sendText(ctx, b, chatID, req.locale.T("ask.region_unsupported", regionErr.CountryCode, h.supportedRegionCodes()))
 			return
 		}
 
-		h.logger.Info("Найдено игр", "count", len(items))
-		message := h.formatter.FormatSteamItems(items)
-		h.sendMessage(ctx, b, update.Message.Chat.ID, message)
+		h.logger.Error(ctx, "Ошибка подбора игр", err, "query", query)
+		h.sendText(ctx, b, chatID, errorMessage(req.locale, err, req.locale.T("ask.error")))
+		return
+	}
+
+	h.logger.Info(ctx, "Подобраны игры", "query", query, "region", result.Region.Code, "titles", len(result.Filters.Titles), "found", len(result.Items))
+	h.sendMessage(ctx, b, chatID, h.formatter.FormatDiscovery(req.locale, result))
+}
+
+// supportedRegionCodes возвращает коды поддерживаемых регионов через запятую: "RU, KZ, TR"
+func (h *TelegramHandler) supportedRegionCodes() string {
+	regions := h.settingsService.Regions()
+	codes := make([]string, 0, len(regions))
+	for _, region := range regions {
+		codes = append(codes, region.Code)
+	}
+	return strings.Join(codes, ", ")
+}
+
+// addPriceStats добавляет в карточку цен исторический минимум и оценку цены.
+// Без истории карточка остается полезной, поэтому ошибка только логируется.
+func (h *TelegramHandler) addPriceStats(ctx context.Context, prices *entities.MultiRegionPriceData) {
+	if err := h.priceStatsService.AddStats(ctx, prices); err != nil {
+		h.logger.Error(ctx, "Ошибка получения истории цен для карточки", err, "game", prices.GameName)
+	}
+}
+
+// handleCallback обрабатывает нажатия на inline-кнопки: выбор игры и меню настроек
+func (h *TelegramHandler) handleCallback(ctx context.Context, b *bot.Bot, query *models.CallbackQuery) {
+	// Сообщение старше 48 часов недоступно для редактирования
+	message := query.Message.Message
+	chatID := query.From.ID
+	if message != nil {
+		chatID = message.Chat.ID
+	}
+	req := h.newRequest(ctx, chatID, &query.From)
+
+	// Лимит считается только для поиска цен: кнопки настроек не обращаются к Steam
+	allowed, retryAfter := true, time.Duration(0)
+	if strings.HasPrefix(query.Data, callbackPricePrefix) {
+		allowed, retryAfter = h.limiter.Allow(query.From.ID)
+	}
+
+	// Убираем "часики" на кнопке у пользователя, а при превышении лимита показываем предупреждение
+	answer := &bot.AnswerCallbackQueryParams{CallbackQueryID: query.ID}
+	if !allowed {
+		answer.Text = req.locale.T("ratelimit.slow_down", retryAfterText(req.locale, retryAfter))
+		answer.ShowAlert = true
+	}
+	if _, err := b.AnswerCallbackQuery(ctx, answer); err != nil {
+		h.logger.Error(ctx, "Ошибка ответа на callback", err, "data", query.Data)
+	}
+
+	if !allowed || message == nil {
 		return
 	}
 
-	h.logger.Info("Найдены цены для игры", "game", prices.GameName, "regions", len(prices.Regions))
-	message := h.formatter.FormatMultiRegionPrices(prices)
-	h.sendMessage(ctx, b, update.Message.Chat.ID, message)
+	if rawID, ok := strings.CutPrefix(query.Data, callbackPricePrefix); ok {
+		h.handlePriceCallback(ctx, b, req, message, rawID, query.Data)
+		return
+	}
+	if action, ok := strings.CutPrefix(query.Data, callbackSettingsPrefix); ok {
+		h.handleSettingsCallback(ctx, b, req, message, action)
+	}
+}
+
+// handlePriceCallback обрабатывает выбор игры из списка: ищет цены по App ID
+// и заменяет сообщение со списком на карточку цен
+func (h *TelegramHandler) handlePriceCallback(ctx context.Context, b *bot.Bot, req *request, message *models.Message, rawID, data string) {
+	appID, err := strconv.Atoi(rawID)
+	if err != nil {
+		h.logger.Error(ctx, "Некорректные данные кнопки", err, "data", data)
+		return
+	}
+
+	// Название игры берем из текста нажатой кнопки, чтобы не искать игру заново
+	game := &entities.SteamItem{
+		ID:   appID,
+		Name: findButtonText(message.ReplyMarkup, data),
+	}
+	if game.Name == "" {
+		game.Name = fmt.Sprintf("App %d", appID)
+	}
+
+	h.editMessage(ctx, b, message.Chat.ID, message.ID, presenters.EscapeHTML(req.locale.T("find.loading", game.Name)))
+
+	prices := h.multiRegionService.GetMultiRegionPricesForGame(ctx, game, req.settings)
+	h.logger.Info(ctx, "Найдены цены для выбранной игры", "game", prices.GameName, "regions", len(prices.Regions))
+	h.addPriceStats(ctx, prices)
+
+	h.editMessage(ctx, b, message.Chat.ID, message.ID, h.formatter.FormatMultiRegionPrices(req.locale, prices))
+}
+
+// sendGameChoice отправляет список найденных игр с кнопками выбора
+func (h *TelegramHandler) sendGameChoice(ctx context.Context, b *bot.Bot, req *request, query string, items []entities.SteamItem) {
+	if len(items) > h.maxSearchResults {
+		items = items[:h.maxSearchResults]
+	}
+
+	keyboard := make([][]models.InlineKeyboardButton, 0, len(items))
+	for _, item := range items {
+		keyboard = append(keyboard, []models.InlineKeyboardButton{{
+			Text:         item.Name,
+			CallbackData: callbackPricePrefix + strconv.Itoa(item.ID),
+		}})
+	}
+
+	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      req.chatID,
+		Text:        presenters.EscapeHTML(req.locale.T("find.choose", query)),
+		ParseMode:   presenters.ParseMode,
+		ReplyMarkup: &models.InlineKeyboardMarkup{InlineKeyboard: keyboard},
+	})
+	if err != nil {
+		h.logger.Error(ctx, "Ошибка отправки списка игр", err, "chatID", req.chatID)
+	}
+}
+
+// needsDisambiguation проверяет, нужно ли предлагать выбор игры.
+// Выбор не нужен, если игра одна или первая найденная точно совпадает с запросом.
+func needsDisambiguation(items []entities.SteamItem, query string) bool {
+	if len(items) < 2 {
+		return false
+	}
+	return !strings.EqualFold(strings.TrimSpace(items[0].Name), strings.TrimSpace(query))
+}
+
+// findButtonText возвращает текст кнопки с указанными данными
+func findButtonText(keyboard *models.InlineKeyboardMarkup, data string) string {
+	if keyboard == nil {
+		return ""
+	}
+
+	for _, row := range keyboard.InlineKeyboard {
+		for _, button := range row {
+			if button.CallbackData == data {
+				return button.Text
+			}
+		}
+	}
+
+	return ""
+}
+
+// handleTrack добавляет игру в список отслеживаемых
+func (h *TelegramHandler) handleTrack(ctx context.Context, b *bot.Bot, req *request, query string) {
+	chatID := req.chatID
+	if query == "" {
+		h.sendText(ctx, b, chatID, req.locale.T("track.usage"))
+		return
+	}
+
+	if err := h.validateQuery(req.locale, query); err != nil {
+		h.sendText(ctx, b, chatID, "❌ "+err.Error())
+		return
+	}
+
+	game, err := h.trackService.Track(ctx, chatID, query)
+	switch {
+	case errors.Is(err, usecases.ErrGameNotFound):
+		h.sendText(ctx, b, chatID, req.locale.T("track.not_found"))
+	case errors.Is(err, interfaces.ErrGameAlreadyTracked):
+		h.sendText(ctx, b, chatID, req.locale.T("track.already", game.GameName))
+	case err != nil:
+		h.logger.Error(ctx, "Ошибка добавления игры в отслеживаемые", err, "query", query)
+		h.sendText(ctx, b, chatID, errorMessage(req.locale, err, req.locale.T("track.error")))
+	default:
+		h.logger.Info(ctx, "Игра добавлена в отслеживаемые", "game", game.GameName, "chatID", chatID)
+		h.sendText(ctx, b, chatID, req.locale.T("track.done", game.GameName))
+	}
+}
+
+// handleUntrack удаляет игру из списка отслеживаемых
+func (h *TelegramHandler) handleUntrack(ctx context.Context, b *bot.Bot, req *request, query string) {
+	chatID := req.chatID
+	if query == "" {
+		h.sendText(ctx, b, chatID, req.locale.T("untrack.usage"))
+		return
+	}
+
+	game, err := h.trackService.Untrack(ctx, chatID, query)
+	if err != nil {
+		h.logger.Error(ctx, "Ошибка удаления игры из отслеживаемых", err, "query", query)
+		h.sendText(ctx, b, chatID, req.locale.T("untrack.error"))
+		return
+	}
+	if game == nil {
+		h.sendText(ctx, b, chatID, req.locale.T("untrack.not_found"))
+		return
+	}
+
+	h.sendText(ctx, b, chatID, req.locale.T("untrack.done", game.GameName))
+}
+
+// handleTracked отправляет список отслеживаемых игр
+func (h *TelegramHandler) handleTracked(ctx context.Context, b *bot.Bot, req *request) {
+	games, err := h.trackService.ListTracked(ctx, req.chatID)
+	if err != nil {
+		h.logger.Error(ctx, "Ошибка получения отслеживаемых игр", err, "chatID", req.chatID)
+		h.sendText(ctx, b, req.chatID, req.locale.T("tracked.error"))
+		return
+	}
+
+	h.sendMessage(ctx, b, req.chatID, h.formatter.FormatTrackedGames(req.locale, games))
+}
+
+// handleHistory отправляет график истории цен игры по регионам
+func (h *TelegramHandler) handleHistory(ctx context.Context, b *bot.Bot, req *request, query string) {
+	chatID := req.chatID
+	if query == "" {
+		h.sendText(ctx, b, chatID, req.locale.T("history.usage"))
+		return
+	}
+
+	if err := h.validateQuery(req.locale, query); err != nil {
+		h.sendText(ctx, b, chatID, "❌ "+err.Error())
+		return
+	}
+
+	history, err := h.historyService.GetHistory(ctx, chatID, query, req.settings)
+	switch {
+	case errors.Is(err, usecases.ErrGameNotFound):
+		h.sendText(ctx, b, chatID, req.locale.T("track.not_found"))
+		return
+	case errors.Is(err, usecases.ErrNoPriceHistory):
+		h.sendText(ctx, b, chatID, req.locale.T("history.empty", history.GameName))
+		return
+	case err != nil:
+		h.logger.Error(ctx, "Ошибка получения истории цен", err, "query", query)
+		h.sendText(ctx, b, chatID, errorMessage(req.locale, err, req.locale.T("history.error")))
+		return
+	}
+
+	chart, err := presenters.RenderPriceHistory(history)
+	if err != nil {
+		h.logger.Error(ctx, "Ошибка построения графика цен", err, "game", history.GameName)
+		h.sendText(ctx, b, chatID, req.locale.T("history.error"))
+		return
+	}
+
+	h.sendPhoto(ctx, b, chatID, chart, h.formatter.FormatPriceHistory(req.locale, history))
+}
+
+// isSteamUnavailable проверяет, что ошибка вызвана временной недоступностью Steam
+func isSteamUnavailable(err error) bool {
+	return errors.Is(err, interfaces.ErrRateLimited) || errors.Is(err, interfaces.ErrUpstreamUnavailable)
+}
+
+// isAIBudgetExceeded проверяет, что пользователь исчерпал лимит запросов к AI
+func isAIBudgetExceeded(err error) bool {
+	var budgetErr *interfaces.AIBudgetError
+	return errors.As(err, &budgetErr)
+}
+
+// retryAfterText возвращает время до следующей попытки: "5 секунд", "2 минуты"
+func retryAfterText(locale i18n.Locale, retryAfter time.Duration) string {
+	if retryAfter < time.Minute {
+		return locale.N("duration.seconds", max(1, int(math.Ceil(retryAfter.Seconds()))))
+	}
+	return locale.N("duration.minutes", int(math.Ceil(retryAfter.Minutes())))
+}
+
+// errorMessage возвращает понятное пользователю сообщение об ошибке.
+// Для известных ошибок Steam — конкретное объяснение, для остальных — defaultMessage.
+func errorMessage(locale i18n.Locale, err error, defaultMessage string) string {
+	switch {
+	case errors.Is(err, interfaces.ErrRateLimited):
+		return locale.T("steam.rate_limited")
+	case errors.Is(err, interfaces.ErrUpstreamUnavailable):
+		return locale.T("steam.unavailable")
+	case isAIBudgetExceeded(err):
+		var budgetErr *interfaces.AIBudgetError
+		errors.As(err, &budgetErr)
+		return locale.T("ratelimit.ai", retryAfterText(locale, budgetErr.RetryAfter))
+	default:
+		return defaultMessage
+	}
 }
 
 // validateQuery проверяет валидность поискового запроса
-func (h *TelegramHandler) validateQuery(query string) error {
+func (h *TelegramHandler) validateQuery(locale i18n.Locale, query string) error {
 	if len(query) < 2 {
-		return &ValidationError{Message: "Поисковый запрос слишком короткий (минимум 2 символа)"}
+		return &ValidationError{Message: locale.T("query.too_short")}
 	}
 	if len(query) > 200 {
-		return &ValidationError{Message: "Поисковый запрос слишком длинный (максимум 200 символов)"}
+		return &ValidationError{Message: locale.T("query.too_long")}
 	}
 	return nil
 }
 
-// sendMessage отправляет сообщение пользователю
+// sendText отправляет пользователю обычный текст без разметки
+func (h *TelegramHandler) sendText(ctx context.Context, b *bot.Bot, chatID int64, text string) {
+	h.sendMessage(ctx, b, chatID, presenters.EscapeHTML(text))
+}
+
+// sendMessage отправляет пользователю сообщение в разметке presenters.ParseMode
 func (h *TelegramHandler) sendMessage(ctx context.Context, b *bot.Bot, chatID int64, text string) {
 	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
-		ChatID: chatID,
-		Text:   text,
+		ChatID:    chatID,
+		Text:      text,
+		ParseMode: presenters.ParseMode,
 	})
 	if err != nil {
-		h.logger.Error("Ошибка отправки сообщения", err, "chatID", chatID)
+		h.logger.Error(ctx, "Ошибка отправки сообщения", err, "chatID", chatID)
+	}
+}
+
+// sendPhoto отправляет PNG изображение с подписью в разметке presenters.ParseMode
+func (h *TelegramHandler) sendPhoto(ctx context.Context, b *bot.Bot, chatID int64, photo []byte, caption string) {
+	_, err := b.SendPhoto(ctx, &bot.SendPhotoParams{
+		ChatID:    chatID,
+		Photo:     &models.InputFileUpload{Filename: "chart.png", Data: bytes.NewReader(photo)},
+		Caption:   caption,
+		ParseMode: presenters.ParseMode,
+	})
+	if err != nil {
+		h.logger.Error(ctx, "Ошибка отправки изображения", err, "chatID", chatID)
+	}
+}
+
+// editMessage заменяет текст ранее отправленного сообщения (и убирает кнопки).
+// text должен быть в разметке presenters.ParseMode.
+func (h *TelegramHandler) editMessage(ctx context.Context, b *bot.Bot, chatID int64, messageID int, text string) {
+	h.editMessageWithKeyboard(ctx, b, chatID, messageID, text, nil)
+}
+
+// editMessageWithKeyboard заменяет текст и кнопки ранее отправленного сообщения.
+// Если keyboard равен nil, кнопки убираются.
+func (h *TelegramHandler) editMessageWithKeyboard(ctx context.Context, b *bot.Bot, chatID int64, messageID int, text string, keyboard *models.InlineKeyboardMarkup) {
+	params := &bot.EditMessageTextParams{
+		ChatID:    chatID,
+		MessageID: messageID,
+		Text:      text,
+		ParseMode: presenters.ParseMode,
+	}
+	// Присваиваем только непустую клавиатуру, чтобы не передать в интерфейс типизированный nil
+	if keyboard != nil {
+		params.ReplyMarkup = keyboard
+	}
+
+	_, err := b.EditMessageText(ctx, params)
+	if err != nil {
+		h.logger.Error(ctx, "Ошибка редактирования сообщения", err, "chatID", chatID)
 	}
 }
 
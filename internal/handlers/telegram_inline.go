@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/i18n"
+	"github.com/MaximVod/steambotgo/internal/presenters"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// handleInlineQuery отвечает на inline-запрос (@bot название) списком найденных игр.
+// В чат сразу отправляется заглушка, а карточка цен подставляется
+// после выбора результата (см. handleChosenInlineResult).
+func (h *TelegramHandler) handleInlineQuery(ctx context.Context, b *bot.Bot, inlineQuery *models.InlineQuery) {
+	query := strings.TrimSpace(inlineQuery.Query)
+
+	// Inline-запросы приходят на каждый набранный символ, поэтому при превышении лимита
+	// просто возвращаем пустой список, не отвлекая пользователя сообщениями.
+	// Лимит проверяется до загрузки настроек, чтобы запросы не нагружали и базу
+	allowed := true
+	if inlineQuery.From != nil {
+		allowed, _ = h.inlineLimiter.Allow(inlineQuery.From.ID)
+	}
+
+	locale := i18n.Default
+	if allowed && inlineQuery.From != nil {
+		locale = h.newRequest(ctx, inlineQuery.From.ID, inlineQuery.From).locale
+	}
+
+	results := []models.InlineQueryResult{}
+	// Telegram сам кэширует ответ на одинаковый запрос, не обращаясь к боту.
+	// Пустой ответ из-за лимита одного пользователя не кэшируется
+	cacheTime := 0
+	if allowed {
+		cacheTime = int(h.inlineCacheTime.Seconds())
+	}
+	if allowed && h.validateQuery(locale, query) == nil {
+		items, err := h.searchService.FetchGames(ctx, query)
+		if err != nil {
+			h.logger.Error(ctx, "Ошибка inline поиска игр", err, "query", query)
+			// Пустой ответ из-за сбоя Steam не кэшируется, иначе запрос не будет работать у всех
+			cacheTime = 0
+		}
+
+		if len(items) > h.maxSearchResults {
+			items = items[:h.maxSearchResults]
+		}
+		for _, item := range items {
+			results = append(results, h.inlineArticle(locale, item))
+		}
+	}
+
+	_, err := b.AnswerInlineQuery(ctx, &bot.AnswerInlineQueryParams{
+		InlineQueryID: inlineQuery.ID,
+		Results:       results,
+		CacheTime:     cacheTime,
+		// Описания и текст заглушки на языке пользователя — кэш не должен быть общим
+		IsPersonal: true,
+	})
+	if err != nil {
+		h.logger.Error(ctx, "Ошибка ответа на inline запрос", err, "query", query)
+	}
+}
+
+// handleChosenInlineResult заменяет заглушку выбранного inline-результата на карточку цен.
+// Telegram присылает выбранный результат, только если у бота включен inline feedback.
+func (h *TelegramHandler) handleChosenInlineResult(ctx context.Context, b *bot.Bot, chosen *models.ChosenInlineResult) {
+	// Без inline_message_id отправленное сообщение нельзя отредактировать
+	if chosen.InlineMessageID == "" {
+		return
+	}
+
+	appID, err := strconv.Atoi(chosen.ResultID)
+	if err != nil {
+		h.logger.Error(ctx, "Некорректный ID inline результата", err, "resultID", chosen.ResultID)
+		return
+	}
+
+	// Inline-сообщение не привязано к чату бота — берем настройки выбравшего пользователя
+	req := h.newRequest(ctx, chosen.From.ID, &chosen.From)
+
+	if allowed, retryAfter := h.limiter.Allow(chosen.From.ID); !allowed {
+		text := req.locale.T("ratelimit.slow_down", retryAfterText(req.locale, retryAfter))
+		h.editInlineMessage(ctx, b, req.locale, chosen.InlineMessageID, appID, presenters.EscapeHTML(text))
+		return
+	}
+
+	// Название берем из результатов того же запроса — они уже в кэше поиска
+	game := &entities.SteamItem{ID: appID, Name: fmt.Sprintf("App %d", appID)}
+	items, err := h.searchService.FetchGames(ctx, chosen.Query)
+	if err != nil {
+		h.logger.Error(ctx, "Ошибка inline поиска игр", err, "query", chosen.Query)
+	}
+	for i := range items {
+		if items[i].ID == appID {
+			game = &items[i]
+			break
+		}
+	}
+
+	prices := h.multiRegionService.GetMultiRegionPricesForGame(ctx, game, req.settings)
+	h.addPriceStats(ctx, prices)
+	h.logger.Info(ctx, "Найдены цены для inline результата", "game", prices.GameName, "regions", len(prices.Regions))
+
+	h.editInlineMessage(ctx, b, req.locale, chosen.InlineMessageID, appID, h.formatter.FormatMultiRegionPrices(req.locale, prices))
+}
+
+// editInlineMessage заменяет текст отправленного inline-сообщения, оставляя ссылку на игру.
+// text должен быть в разметке presenters.ParseMode.
+func (h *TelegramHandler) editInlineMessage(ctx context.Context, b *bot.Bot, locale i18n.Locale, inlineMessageID string, appID int, text string) {
+	_, err := b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		InlineMessageID: inlineMessageID,
+		Text:            text,
+		ParseMode:       presenters.ParseMode,
+		ReplyMarkup:     storeKeyboard(locale, appID),
+	})
+	if err != nil {
+		h.logger.Error(ctx, "Ошибка редактирования inline сообщения", err, "appID", appID)
+	}
+}
+
+// inlineArticle создает inline-результат для игры
+func (h *TelegramHandler) inlineArticle(locale i18n.Locale, item entities.SteamItem) *models.InlineQueryResultArticle {
+	description := locale.T("inline.description")
+	if item.Price != nil {
+		description = locale.T("inline.description_price", item.Price.Currency, float64(item.Price.Final)/100)
+	}
+
+	return &models.InlineQueryResultArticle{
+		ID:           strconv.Itoa(item.ID),
+		Title:        item.Name,
+		Description:  description,
+		ThumbnailURL: item.TinyImage,
+		InputMessageContent: &models.InputTextMessageContent{
+			MessageText: locale.T("inline.loading", item.Name),
+		},
+		// Клавиатура обязательна: без нее Telegram не пришлет inline_message_id
+		// и заглушку нельзя будет заменить на карточку цен
+		ReplyMarkup: storeKeyboard(locale, item.ID),
+	}
+}
+
+// storeKeyboard возвращает клавиатуру со ссылкой на страницу игры в Steam
+func storeKeyboard(locale i18n.Locale, appID int) *models.InlineKeyboardMarkup {
+	return &models.InlineKeyboardMarkup{
+		InlineKeyboard: [][]models.InlineKeyboardButton{{
+			{Text: locale.T("inline.open_store"), URL: fmt.Sprintf("https://store.steampowered.com/app/%d", appID)},
+		}},
+	}
+}
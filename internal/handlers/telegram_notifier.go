@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/i18n"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+	"github.com/MaximVod/steambotgo/internal/presenters"
+	"github.com/MaximVod/steambotgo/internal/usecases"
+	"github.com/go-telegram/bot"
+)
+
+// TelegramNotifier отправляет уведомления о снижении цен сообщениями в Telegram
+type TelegramNotifier struct {
+	bot             *bot.Bot
+	formatter       *presenters.MessageFormatter
+	settingsService *usecases.UserSettingsService
+}
+
+// NewTelegramNotifier создает новый отправитель уведомлений
+func NewTelegramNotifier(b *bot.Bot, formatter *presenters.MessageFormatter, settingsService *usecases.UserSettingsService) *TelegramNotifier {
+	return &TelegramNotifier{
+		bot:             b,
+		formatter:       formatter,
+		settingsService: settingsService,
+	}
+}
+
+// NotifyPriceDrop реализует interfaces.PriceDropNotifier.
+func (n *TelegramNotifier) NotifyPriceDrop(ctx context.Context, userChatID int64, drop *entities.PriceDrop) error {
+	// Уведомление приходит не в ответ на сообщение, поэтому язык Telegram неизвестен —
+	// используем язык из настроек чата или язык по умолчанию
+	locale := i18n.Default
+	if settings, err := n.settingsService.Get(ctx, userChatID); err == nil {
+		locale = userLocale(settings, nil)
+	}
+
+	_, err := n.bot.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:    userChatID,
+		Text:      n.formatter.FormatPriceDrop(locale, drop),
+		ParseMode: presenters.ParseMode,
+	})
+	if err != nil {
+		return fmt.Errorf("не удалось отправить уведомление: %w", err)
+	}
+
+	return nil
+}
+
+// Компиляторная проверка реализации интерфейса.
+var _ interfaces.PriceDropNotifier = (*TelegramNotifier)(nil)
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/i18n"
+	"github.com/MaximVod/steambotgo/internal/presenters"
+	"github.com/MaximVod/steambotgo/internal/usecases"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	// callbackSettingsPrefix — префикс данных кнопок меню настроек: "set:<действие>"
+	callbackSettingsPrefix = "set:"
+
+	settingsMain       = "main"
+	settingsRegions    = "regions"
+	settingsCurrencies = "currency"
+	settingsLanguages  = "lang"
+
+	settingsRegionPrefix   = "region:"
+	settingsCurrencyPrefix = "cur:"
+	settingsLanguagePrefix = "lang:"
+
+	// settingsLanguageAuto — значение кнопки "язык как в Telegram"
+	settingsLanguageAuto = "auto"
+)
+
+// handleSettings отправляет текущие настройки чата с меню для их изменения
+func (h *TelegramHandler) handleSettings(ctx context.Context, b *bot.Bot, req *request) {
+	settings, err := h.settingsService.Get(ctx, req.chatID)
+	if err != nil {
+		h.logger.Error(ctx, "Ошибка получения настроек", err, "chatID", req.chatID)
+		h.sendText(ctx, b, req.chatID, req.locale.T("settings.get_error"))
+		return
+	}
+
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      req.chatID,
+		Text:        h.formatSettings(req.locale, settings),
+		ParseMode:   presenters.ParseMode,
+		ReplyMarkup: settingsMainKeyboard(req.locale),
+	})
+	if err != nil {
+		h.logger.Error(ctx, "Ошибка отправки настроек", err, "chatID", req.chatID)
+	}
+}
+
+// handleSettingsCallback обрабатывает нажатия в меню настроек и обновляет сообщение на месте
+func (h *TelegramHandler) handleSettingsCallback(ctx context.Context, b *bot.Bot, req *request, message *models.Message, action string) {
+	chatID := message.Chat.ID
+	locale := req.locale
+
+	var (
+		settings *entities.UserSettings
+		err      error
+	)
+	switch {
+	case strings.HasPrefix(action, settingsRegionPrefix):
+		settings, err = h.settingsService.ToggleRegion(ctx, chatID, strings.TrimPrefix(action, settingsRegionPrefix))
+		action = settingsRegions
+	case strings.HasPrefix(action, settingsCurrencyPrefix):
+		settings, err = h.settingsService.SetHomeCurrency(ctx, chatID, strings.TrimPrefix(action, settingsCurrencyPrefix))
+		action = settingsMain
+	case strings.HasPrefix(action, settingsLanguagePrefix):
+		language := strings.TrimPrefix(action, settingsLanguagePrefix)
+		if language == settingsLanguageAuto {
+			language = ""
+		}
+		settings, err = h.settingsService.SetLanguage(ctx, chatID, language)
+		action = settingsMain
+	default:
+		settings, err = h.settingsService.Get(ctx, chatID)
+	}
+	if err != nil {
+		h.logger.Error(ctx, "Ошибка изменения настроек", err, "chatID", chatID, "action", action)
+		h.editMessage(ctx, b, chatID, message.ID, presenters.EscapeHTML(locale.T("settings.update_error")))
+		return
+	}
+
+	// После смены языка меню сразу перерисовывается на новом языке
+	locale = userLocale(settings, req.user)
+
+	var keyboard *models.InlineKeyboardMarkup
+	switch action {
+	case settingsRegions:
+		keyboard = h.settingsRegionsKeyboard(locale, settings)
+	case settingsCurrencies:
+		keyboard = h.settingsCurrenciesKeyboard(locale, settings)
+	case settingsLanguages:
+		keyboard = settingsLanguagesKeyboard(locale, settings)
+	default:
+		keyboard = settingsMainKeyboard(locale)
+	}
+
+	h.editMessageWithKeyboard(ctx, b, chatID, message.ID, h.formatSettings(locale, settings), keyboard)
+}
+
+// userSettings возвращает настройки чата для поиска цен.
+// Ошибка чтения настроек не должна мешать поиску — тогда используются настройки по умолчанию (nil).
+func (h *TelegramHandler) userSettings(ctx context.Context, chatID int64) *entities.UserSettings {
+	settings, err := h.settingsService.Get(ctx, chatID)
+	if err != nil {
+		h.logger.Error(ctx, "Ошибка получения настроек", err, "chatID", chatID)
+		return nil
+	}
+	return settings
+}
+
+// formatSettings форматирует текущие настройки чата в разметке presenters.ParseMode
+func (h *TelegramHandler) formatSettings(locale i18n.Locale, settings *entities.UserSettings) string {
+	var regions []string
+	for _, region := range usecases.SelectedRegions(h.settingsService.Regions(), settings) {
+		regions = append(regions, region.Flag+" "+regionName(locale, region))
+	}
+
+	language := locale.T("language.name")
+	if settings.Language == "" {
+		language = locale.T("settings.language_from_tg", language)
+	}
+
+	return presenters.EscapeHTML(locale.T("settings.text", strings.Join(regions, ", "), settings.HomeCurrency, language))
+}
+
+// regionName возвращает название региона на языке пользователя
+func regionName(locale i18n.Locale, region entities.Region) string {
+	if name, ok := locale.Lookup("country." + region.Code); ok {
+		return name
+	}
+	return region.Name
+}
+
+// settingsMainKeyboard возвращает главное меню настроек
+func settingsMainKeyboard(locale i18n.Locale) *models.InlineKeyboardMarkup {
+	return &models.InlineKeyboardMarkup{
+		InlineKeyboard: [][]models.InlineKeyboardButton{
+			{{Text: locale.T("settings.regions"), CallbackData: callbackSettingsPrefix + settingsRegions}},
+			{{Text: locale.T("settings.currency"), CallbackData: callbackSettingsPrefix + settingsCurrencies}},
+			{{Text: locale.T("settings.language"), CallbackData: callbackSettingsPrefix + settingsLanguages}},
+		},
+	}
+}
+
+// settingsRegionsKeyboard возвращает кнопки включения и выключения регионов
+func (h *TelegramHandler) settingsRegionsKeyboard(locale i18n.Locale, settings *entities.UserSettings) *models.InlineKeyboardMarkup {
+	var keyboard [][]models.InlineKeyboardButton
+	for _, region := range h.settingsService.Regions() {
+		mark := "▫️"
+		if settings.HasRegion(region.Code) {
+			mark = "✅"
+		}
+		keyboard = append(keyboard, []models.InlineKeyboardButton{{
+			Text:         fmt.Sprintf("%s %s %s", mark, region.Flag, regionName(locale, region)),
+			CallbackData: callbackSettingsPrefix + settingsRegionPrefix + region.Code,
+		}})
+	}
+
+	return &models.InlineKeyboardMarkup{InlineKeyboard: append(keyboard, settingsBackRow(locale))}
+}
+
+// settingsCurrenciesKeyboard возвращает кнопки выбора валюты конвертации
+func (h *TelegramHandler) settingsCurrenciesKeyboard(locale i18n.Locale, settings *entities.UserSettings) *models.InlineKeyboardMarkup {
+	var row []models.InlineKeyboardButton
+	for _, currency := range h.settingsService.Currencies() {
+		text := currency
+		if currency == settings.HomeCurrency {
+			text = "✅ " + currency
+		}
+		row = append(row, models.InlineKeyboardButton{
+			Text:         text,
+			CallbackData: callbackSettingsPrefix + settingsCurrencyPrefix + currency,
+		})
+	}
+
+	return &models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{row, settingsBackRow(locale)}}
+}
+
+// settingsLanguagesKeyboard возвращает кнопки выбора языка.
+// Каждый язык подписан на нем самом, чтобы его можно было найти, не понимая текущий.
+func settingsLanguagesKeyboard(locale i18n.Locale, settings *entities.UserSettings) *models.InlineKeyboardMarkup {
+	var row []models.InlineKeyboardButton
+	for _, language := range i18n.Supported {
+		text := language.T("language.name")
+		if string(language) == settings.Language {
+			text = "✅ " + text
+		}
+		row = append(row, models.InlineKeyboardButton{
+			Text:         text,
+			CallbackData: callbackSettingsPrefix + settingsLanguagePrefix + string(language),
+		})
+	}
+
+	auto := locale.T("settings.language_auto")
+	if settings.Language == "" {
+		auto = "✅ " + auto
+	}
+	autoRow := []models.InlineKeyboardButton{{
+		Text:         auto,
+		CallbackData: callbackSettingsPrefix + settingsLanguagePrefix + settingsLanguageAuto,
+	}}
+
+	return &models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{row, autoRow, settingsBackRow(locale)}}
+}
+
+// settingsBackRow возвращает строку с кнопкой возврата в главное меню настроек
+func settingsBackRow(locale i18n.Locale) []models.InlineKeyboardButton {
+	return []models.InlineKeyboardButton{{Text: locale.T("settings.back"), CallbackData: callbackSettingsPrefix + settingsMain}}
+}
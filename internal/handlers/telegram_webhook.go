@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/MaximVod/steambotgo/internal/logger"
+	"github.com/go-telegram/bot"
+)
+
+// webhookSecretHeader — заголовок, в котором Telegram передает секрет, указанный в setWebhook
+const webhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// NewWebhookHandler возвращает HTTP обработчик вебхука Telegram.
+// Обновления передаются боту b и обрабатываются тем же TelegramHandler, что и в режиме polling.
+// Запросы без правильного секрета отклоняются с 401, чтобы их нельзя было подделать.
+func NewWebhookHandler(b *bot.Bot, secretToken string, logger logger.Logger) http.Handler {
+	updates := b.WebhookHandler()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received := r.Header.Get(webhookSecretHeader)
+		if subtle.ConstantTimeCompare([]byte(received), []byte(secretToken)) != 1 {
+			logger.Info(r.Context(), "Отклонен запрос вебхука с неверным секретом", "remote", r.RemoteAddr)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		updates(w, r)
+	})
+}
@@ -0,0 +1,110 @@
+// Package i18n содержит каталог сообщений бота на поддерживаемых языках.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Locale — язык сообщений бота
+type Locale string
+
+const (
+	Russian Locale = "ru"
+	English Locale = "en"
+
+	// Default — язык, если пользователь его не выбрал и Telegram его не сообщил
+	Default = Russian
+)
+
+// Supported — языки, для которых есть каталог сообщений
+var Supported = []Locale{Russian, English}
+
+// russianSpeaking — языки, носители которых обычно лучше понимают русский, чем английский
+var russianSpeaking = map[string]bool{
+	"ru": true,
+	"uk": true,
+	"be": true,
+	"kk": true,
+	"ky": true,
+	"uz": true,
+}
+
+// Parse возвращает язык сообщений по коду языка из Telegram ("en", "en-US", "kk").
+// Для языков без своего каталога выбирается русский или английский.
+func Parse(languageCode string) Locale {
+	code, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(languageCode)), "-")
+	if code == "" {
+		return Default
+	}
+
+	if IsSupported(code) {
+		return Locale(code)
+	}
+	if russianSpeaking[code] {
+		return Russian
+	}
+	return English
+}
+
+// IsSupported проверяет, что для языка есть каталог сообщений
+func IsSupported(code string) bool {
+	for _, locale := range Supported {
+		if string(locale) == code {
+			return true
+		}
+	}
+	return false
+}
+
+// T возвращает сообщение по ключу, подставляя args через fmt.Sprintf.
+// Если сообщения нет в каталоге языка, используется каталог по умолчанию, а затем сам ключ.
+func (l Locale) T(key string, args ...any) string {
+	return l.format(l.forms(key), 0, args)
+}
+
+// N возвращает сообщение по ключу в форме множественного числа для n.
+// n передается в fmt.Sprintf первым аргументом, перед args.
+func (l Locale) N(key string, n int, args ...any) string {
+	forms := l.forms(key)
+	return l.format(forms, pluralForm(l, n), append([]any{n}, args...))
+}
+
+// Lookup возвращает сообщение по ключу без подстановок и fallback.
+// Нужен для необязательных сообщений, например названий стран.
+func (l Locale) Lookup(key string) (string, bool) {
+	forms, ok := catalogs[l][key]
+	if !ok || len(forms) == 0 {
+		return "", false
+	}
+	return forms[0], true
+}
+
+// forms возвращает формы сообщения с учетом fallback на язык по умолчанию
+func (l Locale) forms(key string) []string {
+	if forms, ok := catalogs[l][key]; ok {
+		return forms
+	}
+	if forms, ok := catalogs[Default][key]; ok {
+		return forms
+	}
+	return []string{key}
+}
+
+// format выбирает форму сообщения и подставляет аргументы
+func (l Locale) format(forms []string, form int, args []any) string {
+	if form >= len(forms) {
+		form = len(forms) - 1
+	}
+	if len(args) == 0 {
+		return forms[form]
+	}
+	return fmt.Sprintf(forms[form], args...)
+}
+
+// catalogs — сообщения по языкам. Обычное сообщение — одна строка,
+// сообщение с числом — формы множественного числа в порядке из pluralForm.
+var catalogs = map[Locale]map[string][]string{
+	Russian: russian,
+	English: english,
+}
@@ -0,0 +1,119 @@
+package i18n
+
+// english — сообщения на английском языке
+var english = map[string][]string{
+	// Команды
+	"find.usage":             {"Please specify a game title after /find"},
+	"find.choose":            {"🔎 Several games match «%s». Pick the one you need:"},
+	"find.error":             {"Something went wrong while searching for the game."},
+	"find.loading":           {"⏳ Looking up prices for %s..."},
+	"track.usage":            {"Please specify a game title after /track"},
+	"track.not_found":        {"❌ Couldn't find a game for this query."},
+	"track.already":          {"ℹ️ You are already tracking %s."},
+	"track.error":            {"Something went wrong while adding the game."},
+	"track.done":             {"✅ You are now tracking %s. I'll let you know when the price drops."},
+	"untrack.usage":          {"Please specify a game title or ID after /untrack"},
+	"untrack.error":          {"Something went wrong while removing the game."},
+	"untrack.not_found":      {"❌ This game is not on your list. See your list: /tracked"},
+	"untrack.done":           {"🗑 You are no longer tracking %s."},
+	"tracked.error":          {"Something went wrong while loading your games."},
+	"history.usage":          {"Please specify a game title or ID after /history"},
+	"history.error":          {"Something went wrong while drawing the price chart."},
+	"history.empty":          {"📭 There is no price history for %s yet. Prices are saved for tracked games — add the game with /track and the chart will appear after the first checks."},
+	"ask.usage":              {"Describe the game you are looking for after /ask. For example: /ask co-op roguelike under 500 rubles in Turkey"},
+	"ask.error":              {"Something went wrong while picking games."},
+	"ask.region_unsupported": {"❌ Prices in region %s are not supported. Available regions: %s"},
+	"ask.header":             {"🎯 Games for “%s”"},
+	"ask.max_price":          {"Price: up to %s"},
+	"ask.region":             {"Region: %s"},
+	"ask.platforms":          {"Platforms: %s"},
+	"ask.controller":         {"🎮 Controller support"},
+
+	// Команды бота: описания для /help и меню Telegram
+	"command.find":     {"game prices in different regions"},
+	"command.ask":      {"find games by description"},
+	"command.track":    {"get notified when a game gets cheaper"},
+	"command.untrack":  {"stop tracking a game"},
+	"command.tracked":  {"list tracked games"},
+	"command.history":  {"price chart by region"},
+	"command.settings": {"regions, currency and language"},
+	"command.help":     {"list commands"},
+	"command.start":    {"start using the bot"},
+	"command.unknown":  {"🤷 Unknown command. See the list: /help"},
+	"help.title":       {"📖 Bot commands:"},
+	"start.greeting":   {"👋 Hi! I show Steam game prices in different regions and let you know about sales."},
+
+	// Ошибки
+	"query.too_short":    {"Search query is too short (at least 2 characters)"},
+	"query.too_long":     {"Search query is too long (at most 200 characters)"},
+	"steam.rate_limited": {"⏳ Steam is limiting requests right now. Please try again in a minute."},
+	"steam.unavailable":  {"⚠️ Steam is unavailable right now. Please try again later."},
+
+	// Ограничение частоты запросов
+	"ratelimit.slow_down": {"🐢 Too many requests. Please try again in %s."},
+	"ratelimit.ai":        {"🐢 Smart search limit reached. Try a more exact title or try again in %s."},
+	"duration.seconds":    {"%d second", "%d seconds"},
+	"duration.minutes":    {"%d minute", "%d minutes"},
+
+	// Настройки
+	"settings.text":             {"⚙️ Settings\n\n🌍 Regions: %s\n💱 Currency: %s\n🗣 Language: %s"},
+	"settings.regions":          {"🌍 Regions"},
+	"settings.currency":         {"💱 Currency"},
+	"settings.language":         {"🗣 Language"},
+	"settings.language_auto":    {"🌐 Same as Telegram"},
+	"settings.language_from_tg": {"🌐 Same as Telegram (%s)"},
+	"settings.back":             {"« Back"},
+	"settings.get_error":        {"Something went wrong while loading your settings."},
+	"settings.update_error":     {"Something went wrong while saving your settings."},
+	"language.name":             {"🇬🇧 English"},
+
+	// Inline-режим
+	"inline.description":       {"Prices in different regions"},
+	"inline.description_price": {"%s %.2f in US · prices in different regions"},
+	"inline.loading":           {"⏳ Loading prices for %s..."},
+	"inline.open_store":        {"Open in Steam"},
+
+	// Карточка цен
+	"prices.not_found":             {"❌ Couldn't find prices for this game."},
+	"prices.corrected.ai":          {"🤖 Nothing found for “%s”, showing “%s” (AI confidence %.0f%%)"},
+	"prices.corrected.catalog":     {"🔎 Nothing found for “%s”, showing “%s”"},
+	"prices.free":                  {"Free"},
+	"prices.not_available":         {"Unavailable"},
+	"prices.not_sold":              {"Not sold in this region"},
+	"prices.timeout":               {"⚠️ Steam didn't respond in time"},
+	"prices.error":                 {"⚠️ Couldn't get the price"},
+	"prices.discount":              {"On sale - %s (instead of %s)"},
+	"prices.converted":             {" (about %.0f %s)"},
+	"prices.lowest":                {"    📉 lowest %s (%s)"},
+	"prices.verdict.all_time_low":  {" · 🔥 all-time low"},
+	"prices.verdict.near_low":      {" · 👍 near the lowest"},
+	"prices.verdict.above_average": {" · ⏳ above average"},
+	"prices.rates":                 {"💱 %s rate as of %s"},
+	"date.layout":                  {"Jan 2, 2006"},
+
+	// Результаты поиска
+	"search.nothing": {"❌ Nothing found."},
+	"search.more":    {"... and %d more result", "... and %d more results"},
+	"search.free":    {"free"},
+	"search.store":   {"Store"},
+
+	// Отслеживаемые игры
+	"tracked.empty": {"📭 You are not tracking any games yet. Add one with /track <title>."},
+	"tracked.title": {"📋 You are tracking %d game:", "📋 You are tracking %d games:"},
+	"tracked.hint":  {"\nTo stop tracking a game: /untrack <title or ID>"},
+
+	// История цен
+	"history.region": {"%s - now %s, lowest %s (%s)"},
+	"history.legend": {"\n🟩 — sales, 🔴 — all-time low"},
+
+	// Уведомление о снижении цены
+	"drop.title":    {"🔥 %s got cheaper!"},
+	"drop.was":      {" (was %s)"},
+	"drop.discount": {" -%d%% off"},
+
+	// Страны
+	"country.RU": {"Russia"},
+	"country.KZ": {"Kazakhstan"},
+	"country.TR": {"Turkey"},
+	"country.PL": {"Poland"},
+}
@@ -0,0 +1,120 @@
+package i18n
+
+// russian — сообщения на русском языке
+var russian = map[string][]string{
+	// Команды
+	"find.usage":             {"Пожалуйста, укажите название игры после команды /find"},
+	"find.choose":            {"🔎 По запросу «%s» найдено несколько игр. Выберите нужную:"},
+	"find.error":             {"Произошла ошибка при поиске игры."},
+	"find.loading":           {"⏳ Ищу цены на %s..."},
+	"track.usage":            {"Пожалуйста, укажите название игры после команды /track"},
+	"track.not_found":        {"❌ Не удалось найти игру по запросу."},
+	"track.already":          {"ℹ️ Вы уже отслеживаете %s."},
+	"track.error":            {"Произошла ошибка при добавлении игры."},
+	"track.done":             {"✅ Теперь вы отслеживаете %s. Я сообщу, когда цена снизится."},
+	"untrack.usage":          {"Пожалуйста, укажите название или ID игры после команды /untrack"},
+	"untrack.error":          {"Произошла ошибка при удалении игры."},
+	"untrack.not_found":      {"❌ Такой игры нет в вашем списке. Посмотреть список: /tracked"},
+	"untrack.done":           {"🗑 Вы больше не отслеживаете %s."},
+	"tracked.error":          {"Произошла ошибка при получении списка игр."},
+	"history.usage":          {"Укажите название игры или ID после /history"},
+	"history.error":          {"Произошла ошибка при построении графика цен."},
+	"history.empty":          {"📭 Для %s история цен пока пуста. Цены сохраняются для отслеживаемых игр — добавьте игру через /track, и график появится после первых проверок."},
+	"ask.usage":              {"Опишите, какую игру ищете, после команды /ask. Например: /ask кооперативный рогалик до 500 рублей в Турции"},
+	"ask.error":              {"Произошла ошибка при подборе игр."},
+	"ask.region_unsupported": {"❌ Цены в регионе %s не поддерживаются. Доступные регионы: %s"},
+	"ask.header":             {"🎯 Подборка по запросу «%s»"},
+	"ask.max_price":          {"Цена: до %s"},
+	"ask.region":             {"Регион: %s"},
+	"ask.platforms":          {"Платформы: %s"},
+	"ask.controller":         {"🎮 С поддержкой геймпада"},
+
+	// Команды бота: описания для /help и меню Telegram
+	"command.find":     {"цены на игру в разных регионах"},
+	"command.ask":      {"подобрать игры по описанию"},
+	"command.track":    {"отслеживать снижение цены игры"},
+	"command.untrack":  {"перестать отслеживать игру"},
+	"command.tracked":  {"список отслеживаемых игр"},
+	"command.history":  {"график цен по регионам"},
+	"command.settings": {"регионы, валюта и язык"},
+	"command.help":     {"список команд"},
+	"command.start":    {"начать работу с ботом"},
+	"command.unknown":  {"🤷 Неизвестная команда. Список команд: /help"},
+	"help.title":       {"📖 Команды бота:"},
+	"start.greeting":   {"👋 Привет! Я показываю цены на игры Steam в разных регионах и сообщаю о скидках."},
+
+	// Ошибки
+	"query.too_short":    {"Поисковый запрос слишком короткий (минимум 2 символа)"},
+	"query.too_long":     {"Поисковый запрос слишком длинный (максимум 200 символов)"},
+	"steam.rate_limited": {"⏳ Steam временно ограничил количество запросов. Попробуйте через минуту."},
+	"steam.unavailable":  {"⚠️ Steam сейчас недоступен. Попробуйте позже."},
+
+	// Ограничение частоты запросов
+	"ratelimit.slow_down": {"🐢 Слишком много запросов. Попробуйте через %s."},
+	"ratelimit.ai":        {"🐢 Лимит умного поиска исчерпан. Уточните название или попробуйте через %s."},
+	"duration.seconds":    {"%d секунду", "%d секунды", "%d секунд"},
+	"duration.minutes":    {"%d минуту", "%d минуты", "%d минут"},
+
+	// Настройки
+	"settings.text":             {"⚙️ Настройки\n\n🌍 Регионы: %s\n💱 Валюта: %s\n🗣 Язык: %s"},
+	"settings.regions":          {"🌍 Регионы"},
+	"settings.currency":         {"💱 Валюта"},
+	"settings.language":         {"🗣 Язык"},
+	"settings.language_auto":    {"🌐 Как в Telegram"},
+	"settings.back":             {"« Назад"},
+	"settings.get_error":        {"Произошла ошибка при получении настроек."},
+	"settings.update_error":     {"Произошла ошибка при изменении настроек."},
+	"settings.language_from_tg": {"🌐 Как в Telegram (%s)"},
+	"language.name":             {"🇷🇺 Русский"},
+
+	// Inline-режим
+	"inline.description":       {"Цены в разных регионах"},
+	"inline.description_price": {"%s %.2f в US · цены в разных регионах"},
+	"inline.loading":           {"⏳ Загружаю цены на %s..."},
+	"inline.open_store":        {"Открыть в Steam"},
+
+	// Карточка цен
+	"prices.not_found":             {"❌ Не удалось найти цены для указанной игры."},
+	"prices.corrected.ai":          {"🤖 По запросу «%s» ничего не нашлось, показываю «%s» (уверенность AI %.0f%%)"},
+	"prices.corrected.catalog":     {"🔎 По запросу «%s» ничего не нашлось, показываю «%s»"},
+	"prices.free":                  {"Бесплатно"},
+	"prices.not_available":         {"Недоступно"},
+	"prices.not_sold":              {"Не продается в регионе"},
+	"prices.timeout":               {"⚠️ Steam не ответил вовремя"},
+	"prices.error":                 {"⚠️ Не удалось получить цену"},
+	"prices.discount":              {"Цена со скидкой - %s (вместо - %s)"},
+	"prices.converted":             {" (около %.0f %s)"},
+	"prices.lowest":                {"    📉 минимум %s (%s)"},
+	"prices.verdict.all_time_low":  {" · 🔥 исторический минимум"},
+	"prices.verdict.near_low":      {" · 👍 близко к минимуму"},
+	"prices.verdict.above_average": {" · ⏳ дороже, чем обычно"},
+	"prices.rates":                 {"💱 Курс %s на %s"},
+	"date.layout":                  {"02.01.2006"},
+
+	// Результаты поиска
+	"search.nothing": {"❌ Ничего не найдено."},
+	"search.more":    {"... и ещё %d результат", "... и ещё %d результата", "... и ещё %d результатов"},
+	"search.free":    {"бесплатно"},
+	"search.store":   {"Store"},
+
+	// Отслеживаемые игры
+	"tracked.empty": {"📭 Вы пока не отслеживаете ни одной игры. Добавьте игру командой /track <название>."},
+	"tracked.title": {"📋 Вы отслеживаете %d игру:", "📋 Вы отслеживаете %d игры:", "📋 Вы отслеживаете %d игр:"},
+	"tracked.hint":  {"\nЧтобы перестать отслеживать игру: /untrack <название или ID>"},
+
+	// История цен
+	"history.region": {"%s - сейчас %s, минимум %s (%s)"},
+	"history.legend": {"\n🟩 — периоды скидок, 🔴 — исторический минимум"},
+
+	// Уведомление о снижении цены
+	"drop.title":    {"🔥 %s подешевела!"},
+	"drop.was":      {" (было %s)"},
+	"drop.discount": {" скидка -%d%%"},
+
+	// Валюты и страны
+	"currency.RUB": {"руб"},
+	"country.RU":   {"Россия"},
+	"country.KZ":   {"Казахстан"},
+	"country.TR":   {"Турция"},
+	"country.PL":   {"Польша"},
+}
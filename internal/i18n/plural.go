@@ -0,0 +1,28 @@
+package i18n
+
+// pluralForm возвращает индекс формы множественного числа для n.
+//
+// Русский: 0 — "1 игра" (1, 21, 31...), 1 — "2 игры" (2-4, 22-24...), 2 — "5 игр" (остальные).
+// Английский: 0 — "1 game", 1 — "2 games".
+func pluralForm(locale Locale, n int) int {
+	if n < 0 {
+		n = -n
+	}
+
+	switch locale {
+	case Russian:
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return 0
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return 1
+		default:
+			return 2
+		}
+	default:
+		if n == 1 {
+			return 0
+		}
+		return 1
+	}
+}
@@ -2,12 +2,30 @@ package interfaces
 
 import (
 	"context"
+	"fmt"
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
 )
 
+// AIBudgetError возвращается, если пользователь исчерпал лимит запросов к AI.
+type AIBudgetError struct {
+	RetryAfter time.Duration // через сколько появится следующий запрос
+}
+
+func (e *AIBudgetError) Error() string {
+	return fmt.Sprintf("превышен лимит запросов к AI, повторите через %s", e.RetryAfter.Round(time.Second))
+}
+
 // AiAPI определяет методы для работы с AI.
 type AiAPI interface {
 	// SearchGamesByUserQuery ищет игры по запросу юзера, когда Стим не нашел его игры.
-	// Возвращает список найденных игр (может быть пустым — не ошибка!).
+	// Возвращает названия-кандидаты по убыванию уверенности (может быть пустым — не ошибка!).
+	// В случае сетевой/парсинг-ошибки — возвращает error.
+	SearchGamesByUserQuery(ctx context.Context, query string) ([]entities.AICandidate, error)
+
+	// ParseDiscoveryQuery превращает свободный запрос ("кооперативный рогалик до 500 рублей в Турции")
+	// в фильтры и список подходящих игр, которые затем проверяются по данным Steam.
 	// В случае сетевой/парсинг-ошибки — возвращает error.
-	SearchGamesByUserQuery(ctx context.Context, query string) (string, error)
+	ParseDiscoveryQuery(ctx context.Context, request string) (*entities.DiscoveryFilters, error)
 }
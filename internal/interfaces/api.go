@@ -2,9 +2,19 @@ package interfaces
 
 import (
 	"context"
+	"errors"
+
 	"github.com/MaximVod/steambotgo/internal/entities"
 )
 
+var (
+	// ErrRateLimited возвращается, если Steam ограничил частоту запросов (429) и повторы не помогли.
+	ErrRateLimited = errors.New("Steam ограничил частоту запросов")
+
+	// ErrUpstreamUnavailable возвращается, если Steam недоступен (5xx, сетевые ошибки) и повторы не помогли.
+	ErrUpstreamUnavailable = errors.New("Steam временно недоступен")
+)
+
 // SteamAPI определяет методы для работы с API Steam.
 type SteamAPI interface {
 	// SearchGamesByName ищет игры по названию.
@@ -20,4 +30,9 @@ type SteamAPI interface {
 	// Возвращает информацию об игре с ценами в указанной стране.
 	// Если gameID указан (не 0), ищет игру с этим ID в результатах поиска.
 	GetGamePricesByCountryCode(ctx context.Context, query string, countryCode string, gameID int) (*entities.SteamItem, error)
+
+	// GetAppPrices получает цены приложений в магазине указанной страны (appdetails, price_overview).
+	// Возвращает цены по App ID. Если приложение бесплатное, в карте для него будет nil,
+	// а если недоступно в регионе — его не будет в карте совсем.
+	GetAppPrices(ctx context.Context, appIDs []int, countryCode string) (map[int]*entities.PriceInfo, error)
 }
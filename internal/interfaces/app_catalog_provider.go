@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+)
+
+// AppCatalogProvider определяет источник списка всех приложений Steam.
+type AppCatalogProvider interface {
+	// GetApps загружает список приложений.
+	// В случае сетевой/парсинг-ошибки — возвращает error.
+	GetApps(ctx context.Context) ([]entities.SteamApp, error)
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// CacheStore — постоянное хранилище кэша, переживающее перезапуск бота.
+type CacheStore interface {
+	// Get возвращает значение по ключу, если оно есть и не устарело.
+	// Возвращает false, если значения нет (не ошибка!).
+	Get(ctx context.Context, key string) ([]byte, time.Time, bool, error)
+
+	// Set сохраняет значение до expiresAt.
+	Set(ctx context.Context, key string, value []byte, expiresAt time.Time) error
+
+	// DeleteExpired удаляет устаревшие значения.
+	DeleteExpired(ctx context.Context) error
+}
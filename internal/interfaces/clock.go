@@ -0,0 +1,12 @@
+package interfaces
+
+import "time"
+
+// Clock абстрагирует системное время, чтобы фоновые задачи можно было тестировать.
+type Clock interface {
+	// Now возвращает текущее время.
+	Now() time.Time
+
+	// After возвращает канал, в который придет время через d.
+	After(d time.Duration) <-chan time.Time
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+)
+
+// CurrencyRatesProvider определяет источник актуальных курсов валют.
+type CurrencyRatesProvider interface {
+	// GetRates загружает текущие курсы валют.
+	// В случае сетевой/парсинг-ошибки — возвращает error.
+	GetRates(ctx context.Context) (*entities.CurrencyRates, error)
+}
@@ -2,12 +2,36 @@ package interfaces
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/MaximVod/steambotgo/internal/entities"
 )
 
+// ErrGameAlreadyTracked возвращается, если пользователь уже отслеживает эту игру.
+var ErrGameAlreadyTracked = errors.New("игра уже отслеживается")
+
 // GameRepository для записи игр в базу данных.
 type GameRepository interface {
 	// SaveTrackedGame сохраняет игру в базу данных.
+	// Заполняет ID и CreatedAt у переданной игры.
+	// Если пользователь уже отслеживает игру — возвращает ErrGameAlreadyTracked.
 	SaveTrackedGame(ctx context.Context, game *entities.TrackedGame) error
-}
\ No newline at end of file
+
+	// DeleteTrackedGame удаляет игру из списка отслеживаемых пользователем.
+	// Возвращает false, если такой записи не было (не ошибка!).
+	DeleteTrackedGame(ctx context.Context, userChatID int64, gameID int64) (bool, error)
+
+	// GetTrackedGamesByUser возвращает все игры, которые отслеживает пользователь.
+	GetTrackedGamesByUser(ctx context.Context, userChatID int64) ([]entities.TrackedGame, error)
+
+	// GetGamesDueForCheck возвращает игры, которые не проверялись с момента checkedBefore
+	// (или не проверялись ни разу), в порядке last_checked — самые давние первыми.
+	GetGamesDueForCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]entities.TrackedGame, error)
+
+	// GetSubscribers возвращает ID чатов всех пользователей, которые отслеживают игру.
+	GetSubscribers(ctx context.Context, gameID int64) ([]int64, error)
+
+	// MarkGameChecked обновляет last_checked у всех записей игры.
+	MarkGameChecked(ctx context.Context, gameID int64, checkedAt time.Time) error
+}
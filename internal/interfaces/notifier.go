@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+)
+
+// PriceDropNotifier отправляет пользователям уведомления о снижении цен.
+type PriceDropNotifier interface {
+	// NotifyPriceDrop уведомляет пользователя (чат) о снижении цены игры.
+	NotifyPriceDrop(ctx context.Context, userChatID int64, drop *entities.PriceDrop) error
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+)
+
+// PriceSnapshotRepository для записи истории цен в базу данных.
+type PriceSnapshotRepository interface {
+	// SaveSnapshot сохраняет снимок цены.
+	SaveSnapshot(ctx context.Context, snapshot *entities.PriceSnapshot) error
+
+	// GetLatestSnapshot возвращает последний снимок цены игры в регионе.
+	// Возвращает nil, если цена еще ни разу не сохранялась (не ошибка!).
+	GetLatestSnapshot(ctx context.Context, gameID int64, countryCode string) (*entities.PriceSnapshot, error)
+
+	// GetSnapshots возвращает все снимки цены игры во всех регионах по возрастанию времени проверки.
+	GetSnapshots(ctx context.Context, gameID int64) ([]entities.PriceSnapshot, error)
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+)
+
+// UserSettingsRepository для хранения настроек чатов.
+type UserSettingsRepository interface {
+	// GetSettings возвращает настройки чата.
+	// Возвращает nil, если чат еще ничего не настраивал (не ошибка!).
+	GetSettings(ctx context.Context, chatID int64) (*entities.UserSettings, error)
+
+	// SaveSettings создает или обновляет настройки чата.
+	SaveSettings(ctx context.Context, settings *entities.UserSettings) error
+}
@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// correlationIDKey — имя поля с ID корреляции в записях лога
+const correlationIDKey = "correlation_id"
+
+// correlationIDContextKey — ключ ID корреляции в context.Context
+type correlationIDContextKey struct{}
+
+// WithCorrelationID сохраняет в ctx ID корреляции: все записи лога с этим ctx
+// (обработка одного обновления Telegram, один запуск фоновой задачи) получат одинаковый ID
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDContextKey{}, id)
+}
+
+// CorrelationID возвращает ID корреляции из ctx или пустую строку
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDContextKey{}).(string)
+	return id
+}
+
+// NewCorrelationID создает случайный ID корреляции
+func NewCorrelationID() string {
+	b := make([]byte, 8)
+	// crypto/rand.Read не возвращает ошибок на поддерживаемых платформах
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -1,69 +1,94 @@
 package logger
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"strings"
 )
 
-// Logger определяет интерфейс для логирования
+// Logger определяет интерфейс для логирования.
+// args — пары ключ/значение ("query", query), как в log/slog.
+// Из ctx в запись попадает ID корреляции (см. WithCorrelationID).
 type Logger interface {
-	Info(msg string, args ...interface{})
-	Error(msg string, err error, args ...interface{})
-	Debug(msg string, args ...interface{})
+	Info(ctx context.Context, msg string, args ...any)
+	Error(ctx context.Context, msg string, err error, args ...any)
+	Debug(ctx context.Context, msg string, args ...any)
 }
 
-// StandardLogger реализует Logger используя стандартный log пакет
-type StandardLogger struct {
-	infoLog  *log.Logger
-	errorLog *log.Logger
-	debugLog *log.Logger
+// SlogLogger реализует Logger поверх log/slog
+type SlogLogger struct {
+	logger *slog.Logger
 }
 
-// New создает новый стандартный логгер
-func New() Logger {
-	return &StandardLogger{
-		infoLog:  log.New(os.Stdout, "[INFO] ", log.LstdFlags|log.Lshortfile),
-		errorLog: log.New(os.Stderr, "[ERROR] ", log.LstdFlags|log.Lshortfile),
-		debugLog: log.New(os.Stdout, "[DEBUG] ", log.LstdFlags|log.Lshortfile),
-	}
+// New создает логгер, который пишет в stdout.
+// format — "text" или "json", level — "debug", "info", "warn" или "error".
+func New(format, level string) (Logger, error) {
+	return NewWithWriter(os.Stdout, format, level)
 }
 
-func (l *StandardLogger) Info(msg string, args ...interface{}) {
-	if len(args) > 0 {
-		l.infoLog.Printf(msg, args...)
-	} else {
-		l.infoLog.Println(msg)
+// NewWithWriter создает логгер, который пишет в w
+func NewWithWriter(w io.Writer, format, level string) (Logger, error) {
+	var slogLevel slog.Level
+	if err := slogLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("неизвестный уровень логирования: %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: slogLevel}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "text":
+		handler = slog.NewTextHandler(w, opts)
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("неизвестный формат логов: %q", format)
 	}
+
+	return &SlogLogger{logger: slog.New(&contextHandler{Handler: handler})}, nil
+}
+
+func (l *SlogLogger) Info(ctx context.Context, msg string, args ...any) {
+	l.logger.InfoContext(ctx, msg, args...)
 }
 
-func (l *StandardLogger) Error(msg string, err error, args ...interface{}) {
+func (l *SlogLogger) Error(ctx context.Context, msg string, err error, args ...any) {
 	if err != nil {
-		if len(args) > 0 {
-			l.errorLog.Printf(msg+": %v", append(args, err)...)
-		} else {
-			l.errorLog.Printf(msg+": %v", err)
-		}
-	} else {
-		if len(args) > 0 {
-			l.errorLog.Printf(msg, args...)
-		} else {
-			l.errorLog.Println(msg)
-		}
+		args = append(args, slog.Any("error", err))
 	}
+	l.logger.ErrorContext(ctx, msg, args...)
+}
+
+func (l *SlogLogger) Debug(ctx context.Context, msg string, args ...any) {
+	l.logger.DebugContext(ctx, msg, args...)
+}
+
+// contextHandler добавляет к записям ID корреляции из контекста
+type contextHandler struct {
+	slog.Handler
 }
 
-func (l *StandardLogger) Debug(msg string, args ...interface{}) {
-	if len(args) > 0 {
-		l.debugLog.Printf(msg, args...)
-	} else {
-		l.debugLog.Println(msg)
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := CorrelationID(ctx); id != "" {
+		record.AddAttrs(slog.String(correlationIDKey, id))
 	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
 }
 
 // NoOpLogger реализует Logger но ничего не логирует (для тестов)
 type NoOpLogger struct{}
 
-func (n *NoOpLogger) Info(msg string, args ...interface{})   {}
-func (n *NoOpLogger) Error(msg string, err error, args ...interface{}) {}
-func (n *NoOpLogger) Debug(msg string, args ...interface{})  {}
-
+func (n *NoOpLogger) Info(ctx context.Context, msg string, args ...any)             {}
+func (n *NoOpLogger) Error(ctx context.Context, msg string, err error, args ...any) {}
+func (n *NoOpLogger) Debug(ctx context.Context, msg string, args ...any)            {}
@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace — префикс имен всех метрик бота
+const namespace = "steambot"
+
+// Статусы для меток status
+const (
+	StatusOK             = "ok"
+	StatusError          = "error"
+	StatusRateLimited    = "rate_limited"
+	StatusBudgetExceeded = "budget_exceeded"
+	StatusNotFound       = "not_found" // команда не зарегистрирована
+)
+
+// Операции AI для метки operation
+const (
+	AIOperationCorrectTitle = "correct_title" // исправление названия, которое не нашел Steam
+	AIOperationDiscover     = "discover"      // подбор игр по свободному запросу (/ask)
+)
+
+// Metrics собирает метрики бота в формате Prometheus.
+// Методы можно вызывать у nil — так метрики отключаются без проверок в вызывающем коде.
+type Metrics struct {
+	clock    interfaces.Clock
+	registry *prometheus.Registry
+
+	commands       *prometheus.CounterVec
+	steamRequests  *prometheus.HistogramVec
+	aiRequests     *prometheus.HistogramVec
+	telegramErrors *prometheus.CounterVec
+
+	// Время последнего успешного и неуспешного запроса к Steam (UnixNano) для проверки готовности
+	lastSteamSuccess atomic.Int64
+	lastSteamFailure atomic.Int64
+}
+
+// New создает метрики в отдельном реестре (вместе с метриками Go runtime и процесса)
+func New(clock interfaces.Clock) *Metrics {
+	m := &Metrics{
+		clock:    clock,
+		registry: prometheus.NewRegistry(),
+		commands: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "commands_total",
+			Help:      "Обработанные команды бота.",
+		}, []string{"command", "status"}),
+		steamRequests: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "steam_request_duration_seconds",
+			Help:      "Длительность HTTP запросов к Steam (каждой попытки, включая повторы).",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint", "country", "status"}),
+		aiRequests: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "ai_request_duration_seconds",
+			Help:      "Длительность запросов к AI по операциям.",
+			Buckets:   []float64{0.25, 0.5, 1, 2, 4, 8, 16, 32},
+		}, []string{"operation", "status"}),
+		telegramErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "telegram_errors_total",
+			Help:      "Неуспешные вызовы Telegram Bot API (отправка и редактирование сообщений и т.д.).",
+		}, []string{"method"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.commands,
+		m.steamRequests,
+		m.aiRequests,
+		m.telegramErrors,
+	)
+
+	return m
+}
+
+// Handler возвращает HTTP обработчик /metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveCommand учитывает команду. command должен быть из известного списка
+// (не текст пользователя), иначе число временных рядов не ограничено.
+func (m *Metrics) ObserveCommand(command, status string) {
+	if m == nil {
+		return
+	}
+	m.commands.WithLabelValues(command, status).Inc()
+}
+
+// ObserveSteamRequest учитывает запрос к Steam и запоминает, отвечает ли Steam
+func (m *Metrics) ObserveSteamRequest(endpoint, country, status string, ok bool, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.steamRequests.WithLabelValues(endpoint, country, status).Observe(duration.Seconds())
+
+	if ok {
+		m.lastSteamSuccess.Store(m.clock.Now().UnixNano())
+	} else {
+		m.lastSteamFailure.Store(m.clock.Now().UnixNano())
+	}
+}
+
+// ObserveAIRequest учитывает запрос к AI. operation — AIOperationCorrectTitle
+// (fallback поиска в Steam) или AIOperationDiscover.
+func (m *Metrics) ObserveAIRequest(operation, status string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.aiRequests.WithLabelValues(operation, status).Observe(duration.Seconds())
+}
+
+// ObserveTelegramError учитывает неуспешный вызов метода Telegram Bot API
+func (m *Metrics) ObserveTelegramError(method string) {
+	if m == nil {
+		return
+	}
+	m.telegramErrors.WithLabelValues(method).Inc()
+}
+
+// RegisterCacheStats публикует счетчики кэша ответов Steam.
+// stats вызывается при каждом чтении /metrics.
+func (m *Metrics) RegisterCacheStats(stats func() (hits, misses uint64, entries int)) {
+	if m == nil {
+		return
+	}
+
+	m.registry.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_hits_total",
+			Help:      "Ответы Steam, взятые из кэша.",
+		}, func() float64 {
+			hits, _, _ := stats()
+			return float64(hits)
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_misses_total",
+			Help:      "Запросы, для которых пришлось обращаться к Steam.",
+		}, func() float64 {
+			_, misses, _ := stats()
+			return float64(misses)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cache_entries",
+			Help:      "Записей в кэше в памяти.",
+		}, func() float64 {
+			_, _, entries := stats()
+			return float64(entries)
+		}),
+	)
+}
+
+// SteamCheck возвращает проверку готовности: Steam считается недоступным, если последний
+// запрос к нему неуспешен и успешных запросов не было дольше maxAge.
+// Пока запросов к Steam не было, проверка проходит.
+func (m *Metrics) SteamCheck(maxAge time.Duration) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		lastSuccess, lastFailure := m.lastSteamSuccess.Load(), m.lastSteamFailure.Load()
+		if lastFailure <= lastSuccess {
+			return nil
+		}
+
+		if lastSuccess == 0 {
+			return fmt.Errorf("нет успешных запросов к Steam")
+		}
+
+		since := m.clock.Now().Sub(time.Unix(0, lastSuccess))
+		if since > maxAge {
+			return fmt.Errorf("нет успешных запросов к Steam %s", since.Round(time.Second))
+		}
+
+		return nil
+	}
+}
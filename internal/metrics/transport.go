@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// roundTripFunc позволяет использовать функцию как http.RoundTripper
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// SteamTransport измеряет HTTP запросы к Steam: endpoint — последний сегмент пути
+// (storesearch, appdetails), country — параметр cc запроса.
+// Если метрики отключены, возвращает next без изменений.
+func (m *Metrics) SteamTransport(next http.RoundTripper) http.RoundTripper {
+	if m == nil {
+		return next
+	}
+
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		start := m.clock.Now()
+		resp, err := next.RoundTrip(req)
+		duration := m.clock.Now().Sub(start)
+
+		status, ok := StatusError, false
+		if err == nil {
+			status = strconv.Itoa(resp.StatusCode)
+			// 4xx (кроме 429) — ошибка запроса, а не недоступность Steam
+			ok = resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests
+		}
+
+		endpoint := path.Base(strings.TrimSuffix(req.URL.Path, "/"))
+		country := strings.ToUpper(req.URL.Query().Get("cc"))
+		m.ObserveSteamRequest(endpoint, country, status, ok, duration)
+
+		return resp, err
+	})
+}
+
+// TelegramTransport учитывает неуспешные вызовы Telegram Bot API по имени метода.
+// Telegram отвечает на ошибки кодами 4xx/5xx, поэтому тело ответа разбирать не нужно.
+// Если метрики отключены, возвращает next без изменений.
+func (m *Metrics) TelegramTransport(next http.RoundTripper) http.RoundTripper {
+	if m == nil {
+		return next
+	}
+
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+
+		// Отмена контекста (остановка бота) — не ошибка Telegram
+		if req.Context().Err() != nil {
+			return resp, err
+		}
+
+		// Путь запроса — /bot<token>/<method>: в метку попадает только метод, без токена
+		if err != nil || resp.StatusCode != http.StatusOK {
+			m.ObserveTelegramError(path.Base(req.URL.Path))
+		}
+
+		return resp, err
+	})
+}
@@ -2,100 +2,382 @@ package presenters
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 
 	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/i18n"
+	"github.com/go-telegram/bot/models"
 )
 
 const (
 	maxSearchResults = 5
+
+	// ParseMode — режим разметки, в котором MessageFormatter формирует сообщения.
+	// Сообщения форматтера нужно отправлять именно с ним.
+	ParseMode = models.ParseModeHTML
+)
+
+// htmlEscaper экранирует символы, которые Telegram воспринимает как HTML-разметку
+var htmlEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+)
+
+// EscapeHTML подготавливает обычный текст (названия игр, тексты сообщений)
+// к отправке в режиме ParseMode
+func EscapeHTML(text string) string {
+	return htmlEscaper.Replace(text)
+}
+
+// RegionSortMode задает порядок регионов в карточке цен
+type RegionSortMode string
+
+const (
+	// SortRegionsByConfig — порядок регионов из конфигурации
+	SortRegionsByConfig RegionSortMode = "config"
+	// SortRegionsByPrice — сначала самые дешевые (по цене в валюте пользователя)
+	SortRegionsByPrice RegionSortMode = "cheapest"
+	// SortRegionsByDiscount — сначала самые большие скидки
+	SortRegionsByDiscount RegionSortMode = "discount"
 )
 
+// ParseRegionSortMode проверяет и возвращает режим сортировки регионов
+func ParseRegionSortMode(mode string) (RegionSortMode, error) {
+	switch RegionSortMode(mode) {
+	case SortRegionsByConfig, SortRegionsByPrice, SortRegionsByDiscount:
+		return RegionSortMode(mode), nil
+	default:
+		return "", fmt.Errorf("неизвестный режим сортировки регионов: %q", mode)
+	}
+}
+
 // MessageFormatter форматирует данные для отправки в Telegram
-type MessageFormatter struct{}
+type MessageFormatter struct {
+	sortMode RegionSortMode
+}
 
 // NewMessageFormatter создает новый форматтер сообщений
-func NewMessageFormatter() *MessageFormatter {
-	return &MessageFormatter{}
+func NewMessageFormatter(sortMode RegionSortMode) *MessageFormatter {
+	return &MessageFormatter{
+		sortMode: sortMode,
+	}
 }
 
 // FormatMultiRegionPrices форматирует данные о многорегиональных ценах
-func (f *MessageFormatter) FormatMultiRegionPrices(data *entities.MultiRegionPriceData) string {
+func (f *MessageFormatter) FormatMultiRegionPrices(locale i18n.Locale, data *entities.MultiRegionPriceData) string {
 	if len(data.Regions) == 0 {
-		return "❌ Не удалось найти цены для указанной игры."
+		return EscapeHTML(locale.T("prices.not_found"))
 	}
 
+	// Строки карточки собираются обычным текстом и экранируются вместе в конце
 	var parts []string
 
-	// Добавляем название игры как заголовок
-	parts = append(parts, fmt.Sprintf("*%s*", data.GameName))
-
 	isAllPricesNotAvailable := false
 
-	gamePriceStatus := "Недоступно"
+	gamePriceStatus := locale.T("prices.not_available")
 
 	// Проверяем на наличие того, есть ли хоть по одному из регионов цена
 	for _, region := range data.Regions {
-		if region.Item.Price != nil {
+		if region.Status == entities.RegionStatusOK && region.Item.Price != nil {
 			isAllPricesNotAvailable = true
 		}
 	}
 
 	if !isAllPricesNotAvailable {
-		gamePriceStatus = "Бесплатно"
+		gamePriceStatus = locale.T("prices.free")
+	}
+
+	// Пользователь должен видеть, что цены показаны не для его запроса, а для исправленного названия
+	if data.Correction != nil {
+		parts = append(parts, f.formatCorrection(locale, data.Correction))
 	}
 
 	// Добавляем информацию о региональных ценах
-	for _, region := range data.Regions {
-		if region.Item.Price != nil {
-			priceText := f.formatPriceText(region)
-			parts = append(parts, fmt.Sprintf("%s - %s", region.CountryFlag, priceText))
-		} else {
-			parts = append(parts, fmt.Sprintf("%s - "+gamePriceStatus, region.CountryFlag))
+	for _, region := range f.sortRegions(data.Regions) {
+		switch region.Status {
+		case entities.RegionStatusOK:
+			if region.Item.Price != nil {
+				priceText := f.formatPriceText(locale, region)
+				parts = append(parts, fmt.Sprintf("%s - %s", region.CountryFlag, priceText))
+				if region.Stats != nil {
+					parts = append(parts, f.formatPriceStats(locale, region.Stats, region.Item.Price.Currency))
+				}
+			} else {
+				parts = append(parts, fmt.Sprintf("%s - %s", region.CountryFlag, gamePriceStatus))
+			}
+		case entities.RegionStatusUnavailable:
+			parts = append(parts, fmt.Sprintf("%s - %s", region.CountryFlag, locale.T("prices.not_sold")))
+		case entities.RegionStatusTimeout:
+			parts = append(parts, fmt.Sprintf("%s - %s", region.CountryFlag, locale.T("prices.timeout")))
+		default:
+			parts = append(parts, fmt.Sprintf("%s - %s", region.CountryFlag, locale.T("prices.error")))
 		}
 	}
 
-	parts = append(parts, fmt.Sprintf("https://store.steampowered.com/app/%v", data.ID))
+	if !data.RatesDate.IsZero() {
+		parts = append(parts, locale.T("prices.rates", data.RatesSource, data.RatesDate.Format(locale.T("date.layout"))))
+	}
+
+	parts = append(parts, storeURL(data.ID))
 
-	return strings.Join(parts, "\n")
+	// Название игры — заголовок карточки
+	return fmt.Sprintf("<b>%s</b>\n%s", EscapeHTML(data.GameName), EscapeHTML(strings.Join(parts, "\n")))
+}
+
+// FormatDiscovery форматирует подборку игр: фильтры, которые AI извлек из запроса,
+// чтобы пользователь видел, как его поняли, и найденные игры с ценами в регионе
+func (f *MessageFormatter) FormatDiscovery(locale i18n.Locale, result *entities.DiscoveryResult) string {
+	filters := result.Filters
+
+	// Жанры не показываются: по ним AI подбирает названия, а проверить их
+	// по данным поиска Steam нельзя — это не примененный фильтр
+	var parts []string
+	if filters.MaxPrice > 0 {
+		currency := filters.MaxPriceCurrency
+		if currency == "" {
+			currency = result.Region.Currency
+		}
+		parts = append(parts, locale.T("ask.max_price", fmt.Sprintf("%g %s", filters.MaxPrice, currency)))
+	}
+	parts = append(parts, locale.T("ask.region", result.Region.Flag+" "+result.Region.Code))
+	if len(filters.Platforms) > 0 {
+		parts = append(parts, locale.T("ask.platforms", strings.Join(filters.Platforms, ", ")))
+	}
+	if filters.ControllerSupport {
+		parts = append(parts, locale.T("ask.controller"))
+	}
+
+	header := fmt.Sprintf("<b>%s</b>\n%s", EscapeHTML(locale.T("ask.header", result.Query)), EscapeHTML(strings.Join(parts, "\n")))
+	return header + "\n\n" + f.FormatSteamItems(locale, result.Items)
+}
+
+// formatCorrection описывает исправление запроса. Уверенность показывается только для AI:
+// похожесть названия из каталога пользователю ни о чем не говорит.
+func (f *MessageFormatter) formatCorrection(locale i18n.Locale, correction *entities.QueryCorrection) string {
+	if correction.Source == entities.CorrectionSourceAI {
+		return locale.T("prices.corrected.ai", correction.Query, correction.Title, math.Round(correction.Confidence*100))
+	}
+	return locale.T("prices.corrected.catalog", correction.Query, correction.Title)
 }
 
 // FormatSteamItems форматирует список игр для отправки
-func (f *MessageFormatter) FormatSteamItems(items []entities.SteamItem) string {
+func (f *MessageFormatter) FormatSteamItems(locale i18n.Locale, items []entities.SteamItem) string {
 	if len(items) == 0 {
-		return "❌ Ничего не найдено."
+		return EscapeHTML(locale.T("search.nothing"))
 	}
 
 	var parts []string
 	for i, item := range items {
 		if i >= maxSearchResults {
-			parts = append(parts, fmt.Sprintf("\n<i>... и ещё %d результатов</i>", len(items)-maxSearchResults))
+			parts = append(parts, fmt.Sprintf("\n<i>%s</i>", EscapeHTML(locale.N("search.more", len(items)-maxSearchResults))))
 			break
 		}
-		parts = append(parts, item.String())
+		parts = append(parts, f.formatSteamItem(locale, item))
 	}
 
 	return strings.Join(parts, "\n\n")
 }
 
+// formatSteamItem возвращает человекочитаемое представление игры для Telegram.
+func (f *MessageFormatter) formatSteamItem(locale i18n.Locale, s entities.SteamItem) string {
+	// Цена
+	price := locale.T("search.free")
+	if s.Price != nil {
+		// Форматируем как $9.99 (не 999 центов!)
+		price = fmt.Sprintf("%s %.2f", s.Price.Currency, float64(s.Price.Final)/100)
+	}
+
+	// Платформы (эмодзи)
+	var platforms string
+	if s.Platforms.Windows {
+		platforms += "🖥️"
+	}
+	if s.Platforms.Mac {
+		platforms += "🍎"
+	}
+	if s.Platforms.Linux {
+		platforms += "🐧"
+	}
+	if platforms == "" {
+		platforms = "—"
+	}
+
+	// Metascore (если есть)
+	metascore := ""
+	if !s.Metascore.IsEmpty() {
+		metascore = fmt.Sprintf(" ⭐ %s", s.Metascore.String())
+	}
+
+	// Controller support (если есть)
+	controller := ""
+	if s.ControllerSupport != "" {
+		controller = fmt.Sprintf(" 🎮 %s", s.ControllerSupport)
+	}
+
+	// Формируем строку в разметке ParseMode
+	return fmt.Sprintf(
+		"🎮 <b>%s</b>\n"+
+			"💰 %s\n"+
+			"📊%s\n"+
+			"💻 %s\n"+
+			"🔗 <a href=\"%s\">%s</a>%s",
+		EscapeHTML(s.Name),
+		EscapeHTML(price),
+		EscapeHTML(metascore),
+		platforms,
+		EscapeHTML(storeURL(s.ID)),
+		EscapeHTML(locale.T("search.store")),
+		EscapeHTML(controller),
+	)
+}
+
+// sortRegions возвращает регионы в порядке, заданном режимом сортировки.
+// Исходный срез не меняется. Регионы без цены всегда идут в конце,
+// а при равенстве сохраняется порядок из конфигурации.
+func (f *MessageFormatter) sortRegions(regions []*entities.RegionalPriceInfo) []*entities.RegionalPriceInfo {
+	sorted := make([]*entities.RegionalPriceInfo, len(regions))
+	copy(sorted, regions)
+
+	if f.sortMode == SortRegionsByConfig || f.sortMode == "" {
+		return sorted
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		aOK, bOK := a.Status == entities.RegionStatusOK, b.Status == entities.RegionStatusOK
+		if aOK != bOK {
+			return aOK
+		}
+		if !aOK {
+			return false
+		}
+
+		switch f.sortMode {
+		case SortRegionsByPrice:
+			return a.Converted < b.Converted
+		case SortRegionsByDiscount:
+			return regionDiscount(a) > regionDiscount(b)
+		}
+		return false
+	})
+
+	return sorted
+}
+
+// regionDiscount возвращает процент скидки в регионе (0 — без скидки или бесплатно)
+func regionDiscount(region *entities.RegionalPriceInfo) int {
+	if region.Item.Price == nil {
+		return 0
+	}
+	return region.Item.Price.Discount()
+}
+
 // formatPriceText форматирует текст цены в зависимости от скидки и страны
-func (f *MessageFormatter) formatPriceText(region *entities.RegionalPriceInfo) string {
+func (f *MessageFormatter) formatPriceText(locale i18n.Locale, region *entities.RegionalPriceInfo) string {
 	finalPrice := fmt.Sprintf("%.2f %s", float64(region.Item.Price.Final)/100, region.Item.Price.Currency)
 	initialPrice := fmt.Sprintf("%.2f %s", float64(region.Item.Price.Initial)/100, region.Item.Price.Currency)
 
 	hasDiscount := region.Item.Price.Initial > region.Item.Price.Final
-	hasConversion := region.ConvertedRub > 0 && region.CountryCode != "RU"
+	hasConversion := region.Converted > 0 && region.Item.Price.Currency != region.ConvertedCurrency
 
 	var text string
 	if hasDiscount {
-		text = fmt.Sprintf("Цена со скидкой - %s (вместо - %s)", finalPrice, initialPrice)
+		text = locale.T("prices.discount", finalPrice, initialPrice)
 	} else {
 		text = finalPrice
 	}
 
 	if hasConversion {
-		text += fmt.Sprintf(" (около %.0f руб)", region.ConvertedRub)
+		text += locale.T("prices.converted", region.Converted, currencyName(locale, region.ConvertedCurrency))
 	}
 
 	return text
 }
+
+// formatPriceStats форматирует исторический минимум цены в регионе и оценку текущей цены
+func (f *MessageFormatter) formatPriceStats(locale i18n.Locale, stats *entities.PriceStats, currency string) string {
+	lowest := fmt.Sprintf("%.2f %s", float64(stats.LowestPrice)/100, currency)
+	text := locale.T("prices.lowest", lowest, stats.LowestAt.Format(locale.T("date.layout")))
+
+	if stats.Verdict != entities.PriceVerdictNone {
+		text += locale.T("prices.verdict." + string(stats.Verdict))
+	}
+
+	return text
+}
+
+// FormatTrackedGames форматирует список отслеживаемых игр
+func (f *MessageFormatter) FormatTrackedGames(locale i18n.Locale, games []entities.TrackedGame) string {
+	if len(games) == 0 {
+		return EscapeHTML(locale.T("tracked.empty"))
+	}
+
+	parts := []string{locale.N("tracked.title", len(games))}
+	for i, game := range games {
+		parts = append(parts, fmt.Sprintf("%d. %s (ID %d)", i+1, game.GameName, game.GameID))
+	}
+	parts = append(parts, locale.T("tracked.hint"))
+
+	return EscapeHTML(strings.Join(parts, "\n"))
+}
+
+// FormatPriceDrop форматирует уведомление о снижении цены отслеживаемой игры
+func (f *MessageFormatter) FormatPriceDrop(locale i18n.Locale, drop *entities.PriceDrop) string {
+	parts := []string{locale.T("drop.title", drop.GameName)}
+
+	for _, change := range drop.Changes {
+		oldPrice := fmt.Sprintf("%.2f %s", float64(change.OldPrice)/100, change.Currency)
+		newPrice := fmt.Sprintf("%.2f %s", float64(change.NewPrice)/100, change.Currency)
+
+		text := fmt.Sprintf("%s - %s", change.CountryFlag, newPrice)
+		if change.NewPrice < change.OldPrice {
+			text += locale.T("drop.was", oldPrice)
+		}
+		if change.NewDiscount > 0 {
+			text += locale.T("drop.discount", change.NewDiscount)
+		}
+		parts = append(parts, text)
+	}
+
+	parts = append(parts, storeURL(int(drop.GameID)))
+
+	return EscapeHTML(strings.Join(parts, "\n"))
+}
+
+// FormatPriceHistory форматирует подпись к графику истории цен (RenderPriceHistory):
+// текущая цена и исторический минимум в каждом регионе в порядке панелей графика
+func (f *MessageFormatter) FormatPriceHistory(locale i18n.Locale, history *entities.PriceHistory) string {
+	dateLayout := locale.T("date.layout")
+
+	var parts []string
+	for _, region := range history.Regions {
+		latest, lowest := region.Latest(), region.Lowest()
+		parts = append(parts, locale.T(
+			"history.region",
+			region.CountryFlag,
+			fmt.Sprintf("%.2f %s", float64(latest.Price)/100, region.Currency),
+			fmt.Sprintf("%.2f %s", float64(lowest.Price)/100, region.Currency),
+			lowest.CheckedAt.Format(dateLayout),
+		))
+	}
+	parts = append(parts, locale.T("history.legend"))
+
+	return fmt.Sprintf("📈 <b>%s</b>\n%s", EscapeHTML(history.GameName), EscapeHTML(strings.Join(parts, "\n")))
+}
+
+// currencyName возвращает короткое название валюты для текста сообщения
+func currencyName(locale i18n.Locale, currency string) string {
+	if name, ok := locale.Lookup("currency." + currency); ok {
+		return name
+	}
+	return currency
+}
+
+// storeURL возвращает ссылку на страницу игры в Steam
+func storeURL(appID int) string {
+	return fmt.Sprintf("https://store.steampowered.com/app/%d/", appID)
+}
@@ -0,0 +1,158 @@
+package presenters
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/i18n"
+)
+
+// update перезаписывает эталонные файлы: go test ./internal/presenters -update
+var update = flag.Bool("update", false, "перезаписать эталонные файлы в testdata")
+
+// trickyTitles — названия с символами, которые ломают разметку Telegram без экранирования
+var trickyTitles = []struct {
+	name  string // имя эталонного файла
+	title string
+}{
+	{"registered_quotes", `Tom Clancy's Rainbow Six® Siege <Deluxe> & "Gold"`},
+	{"trademark_brackets", `S.T.A.L.K.E.R.™ 2: Heart of Chornobyl [Ultimate_Edition]`},
+	{"markdown_and_tags", `**Bold** & <b>not bold</b> > _italic_`},
+	{"link_injection", `</a><a href="https://evil.example">click</a>`},
+}
+
+func TestFormatMultiRegionPricesGolden(t *testing.T) {
+	formatter := NewMessageFormatter(SortRegionsByConfig)
+
+	for i, tc := range trickyTitles {
+		title := tc.title
+		data := &entities.MultiRegionPriceData{
+			ID:       1091500 + i,
+			GameName: title,
+			Regions: []*entities.RegionalPriceInfo{
+				{
+					CountryCode:       "US",
+					CountryFlag:       "🇺🇸",
+					Status:            entities.RegionStatusOK,
+					Item:              &entities.SteamItem{Name: title, Price: &entities.PriceInfo{Currency: "USD", Initial: 5999, Final: 2999}},
+					Converted:         2700,
+					ConvertedCurrency: "RUB",
+					Stats: &entities.PriceStats{
+						LowestPrice: 2999,
+						LowestAt:    time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC),
+						Verdict:     entities.PriceVerdictAllTimeLow,
+					},
+				},
+				{
+					CountryCode:       "RU",
+					CountryFlag:       "🇷🇺",
+					Status:            entities.RegionStatusOK,
+					Item:              &entities.SteamItem{Name: title, Price: &entities.PriceInfo{Currency: "RUB", Initial: 199900, Final: 199900}},
+					ConvertedCurrency: "RUB",
+				},
+				{CountryCode: "TR", CountryFlag: "🇹🇷", Status: entities.RegionStatusUnavailable},
+				{CountryCode: "KZ", CountryFlag: "🇰🇿", Status: entities.RegionStatusTimeout, Err: errors.New("timeout")},
+				{CountryCode: "AR", CountryFlag: "🇦🇷", Status: entities.RegionStatusError, Err: errors.New("boom")},
+			},
+			RatesSource: "ЦБ РФ",
+			RatesDate:   time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC),
+			Correction: &entities.QueryCorrection{
+				Query:      `<rainbow & six>`,
+				Title:      title,
+				Confidence: 0.87,
+				Source:     entities.CorrectionSourceAI,
+			},
+		}
+
+		for _, locale := range i18n.Supported {
+			name := filepath.Join("price_card", tc.name+"."+string(locale)+".golden")
+			assertGolden(t, name, formatter.FormatMultiRegionPrices(locale, data))
+		}
+	}
+}
+
+func TestFormatSteamItemsGolden(t *testing.T) {
+	formatter := NewMessageFormatter(SortRegionsByConfig)
+
+	var items []entities.SteamItem
+	for i, tc := range trickyTitles {
+		item := entities.SteamItem{
+			ID:        1091500 + i,
+			Name:      tc.title,
+			Platforms: entities.Platforms{Windows: true, Linux: i%2 == 0},
+		}
+		if i%2 == 0 {
+			item.Price = &entities.PriceInfo{Currency: "USD", Initial: 5999, Final: 2999}
+			item.Metascore = "86"
+			item.ControllerSupport = "full"
+		}
+		items = append(items, item)
+	}
+	// Больше maxSearchResults — в конце строка о скрытых результатах
+	items = append(items,
+		entities.SteamItem{ID: 1, Name: `Extra <1>`},
+		entities.SteamItem{ID: 2, Name: `Extra & 2`},
+	)
+
+	for _, locale := range i18n.Supported {
+		assertGolden(t, filepath.Join("search_list", string(locale)+".golden"), formatter.FormatSteamItems(locale, items))
+	}
+}
+
+func TestFormatPriceDropGolden(t *testing.T) {
+	formatter := NewMessageFormatter(SortRegionsByConfig)
+	drop := &entities.PriceDrop{
+		GameID:   1091500,
+		GameName: trickyTitles[0].title,
+		Changes: []entities.PriceChange{
+			{CountryCode: "US", CountryFlag: "🇺🇸", Currency: "USD", OldPrice: 5999, NewPrice: 2999, NewDiscount: 50},
+			{CountryCode: "RU", CountryFlag: "🇷🇺", Currency: "RUB", OldPrice: 199900, NewPrice: 199900, NewDiscount: 10},
+		},
+	}
+
+	for _, locale := range i18n.Supported {
+		assertGolden(t, filepath.Join("price_drop", string(locale)+".golden"), formatter.FormatPriceDrop(locale, drop))
+	}
+}
+
+func TestFormatTrackedGamesGolden(t *testing.T) {
+	formatter := NewMessageFormatter(SortRegionsByConfig)
+
+	var games []entities.TrackedGame
+	for i, tc := range trickyTitles {
+		games = append(games, entities.TrackedGame{GameID: int64(1091500 + i), GameName: tc.title})
+	}
+
+	for _, locale := range i18n.Supported {
+		assertGolden(t, filepath.Join("tracked_games", string(locale)+".golden"), formatter.FormatTrackedGames(locale, games))
+	}
+}
+
+// assertGolden сравнивает got с файлом testdata/name, а с флагом -update перезаписывает его
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("не удалось прочитать эталон (go test -update создаст его): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s: вывод отличается от эталона\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
@@ -0,0 +1,200 @@
+package presenters
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Размеры графика истории цен в пикселях: по одной панели на регион
+const (
+	chartWidth        = 800
+	chartPanelHeight  = 180
+	chartMarginLeft   = 80 // место под подписи цен
+	chartMarginRight  = 20
+	chartMarginTop    = 24 // место под заголовок панели
+	chartMarginBottom = 26 // место под подписи дат
+	chartLineWidth    = 2
+	chartLowRadius    = 5
+)
+
+// Цвета графика
+var (
+	chartBackground = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	chartGrid       = color.RGBA{R: 0xe6, G: 0xe6, B: 0xe6, A: 0xff}
+	chartAxis       = color.RGBA{R: 0x99, G: 0x99, B: 0x99, A: 0xff}
+	chartText       = color.RGBA{R: 0x33, G: 0x33, B: 0x33, A: 0xff}
+	chartLine       = color.RGBA{R: 0x1b, G: 0x6a, B: 0xc9, A: 0xff}
+	chartDiscount   = color.RGBA{R: 0xd9, G: 0xf2, B: 0xd9, A: 0xff}
+	chartLow        = color.RGBA{R: 0xd6, G: 0x28, B: 0x28, A: 0xff}
+)
+
+// RenderPriceHistory рисует историю цен в PNG: на каждый регион своя панель со своей шкалой
+// (валюты регионов разные). Финальная цена рисуется ступенчатой линией — между проверками
+// цена считается неизменной, периоды скидок закрашиваются зеленым, исторический минимум
+// отмечается красной точкой. Подписи только латиницей и цифрами: встроенный шрифт без кириллицы.
+func RenderPriceHistory(history *entities.PriceHistory) ([]byte, error) {
+	if len(history.Regions) == 0 {
+		return nil, fmt.Errorf("нет данных для графика")
+	}
+
+	// Общая ось времени для всех регионов, чтобы панели можно было сравнивать
+	from, to := history.Regions[0].Snapshots[0].CheckedAt, history.Regions[0].Latest().CheckedAt
+	for _, region := range history.Regions[1:] {
+		if first := region.Snapshots[0].CheckedAt; first.Before(from) {
+			from = first
+		}
+		if last := region.Latest().CheckedAt; last.After(to) {
+			to = last
+		}
+	}
+	// Одна проверка — рисуем хотя бы сутки, чтобы линию было видно
+	if !to.After(from) {
+		to = from.Add(24 * time.Hour)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, chartPanelHeight*len(history.Regions)))
+	draw.Draw(img, img.Bounds(), image.NewUniform(chartBackground), image.Point{}, draw.Src)
+
+	for i, region := range history.Regions {
+		panel := image.Rect(
+			chartMarginLeft,
+			i*chartPanelHeight+chartMarginTop,
+			chartWidth-chartMarginRight,
+			(i+1)*chartPanelHeight-chartMarginBottom,
+		)
+		drawPricePanel(img, panel, region, from, to)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("не удалось закодировать график: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// drawPricePanel рисует график цены одного региона в прямоугольнике panel
+func drawPricePanel(img *image.RGBA, panel image.Rectangle, region entities.RegionPriceHistory, from, to time.Time) {
+	minPrice, maxPrice := region.Lowest().Price, region.Snapshots[0].Price
+	for _, snapshot := range region.Snapshots {
+		maxPrice = max(maxPrice, snapshot.Price)
+	}
+	// Отступ сверху и снизу, чтобы линия не сливалась с рамкой
+	padding := max((maxPrice-minPrice)/5, 100)
+	low, high := max(minPrice-padding, 0), maxPrice+padding
+
+	x := func(t time.Time) int {
+		return panel.Min.X + int(float64(panel.Dx())*float64(t.Sub(from))/float64(to.Sub(from)))
+	}
+	y := func(price int) int {
+		return panel.Max.Y - int(float64(panel.Dy())*float64(price-low)/float64(high-low))
+	}
+
+	// Периоды скидок: от снимка со скидкой до следующей проверки
+	for i, snapshot := range region.Snapshots {
+		if snapshot.Discount <= 0 {
+			continue
+		}
+		end := to
+		if i+1 < len(region.Snapshots) {
+			end = region.Snapshots[i+1].CheckedAt
+		}
+		fillRect(img, image.Rect(x(snapshot.CheckedAt), panel.Min.Y, max(x(end), x(snapshot.CheckedAt)+chartLineWidth), panel.Max.Y), chartDiscount)
+	}
+
+	// Сетка и подписи цен: минимум и максимум за период
+	for _, price := range []int{minPrice, maxPrice} {
+		fillRect(img, image.Rect(panel.Min.X, y(price), panel.Max.X, y(price)+1), chartGrid)
+		drawLabel(img, 8, y(price)+4, formatChartPrice(price))
+	}
+	drawFrame(img, panel, chartAxis)
+
+	drawLabel(img, panel.Min.X, panel.Min.Y-8, fmt.Sprintf("%s, %s", region.CountryCode, region.Currency))
+	drawLabel(img, panel.Min.X, panel.Max.Y+16, from.Format("2006-01-02"))
+	toLabel := to.Format("2006-01-02")
+	drawLabel(img, panel.Max.X-labelWidth(toLabel), panel.Max.Y+16, toLabel)
+
+	// Ступенчатая линия цены: горизонтально до следующей проверки, затем вертикально к новой цене
+	half := chartLineWidth / 2
+	for i, snapshot := range region.Snapshots {
+		end := to
+		if i+1 < len(region.Snapshots) {
+			end = region.Snapshots[i+1].CheckedAt
+		}
+		x1, x2, y1 := x(snapshot.CheckedAt), x(end), y(snapshot.Price)
+		fillRect(img, image.Rect(x1, y1-half, x2+half, y1-half+chartLineWidth), chartLine)
+
+		if i+1 < len(region.Snapshots) {
+			y2 := y(region.Snapshots[i+1].Price)
+			fillRect(img, image.Rect(x2-half, min(y1, y2)-half, x2-half+chartLineWidth, max(y1, y2)+half), chartLine)
+		}
+	}
+
+	lowest := region.Lowest()
+	lowX, lowY := x(lowest.CheckedAt), y(lowest.Price)
+	fillCircle(img, lowX, lowY, chartLowRadius, chartLow)
+
+	// Подпись минимума справа над точкой, а у правого края панели — слева
+	lowLabel := "low " + formatChartPrice(lowest.Price)
+	labelX := lowX + chartLowRadius + 2
+	if labelX+labelWidth(lowLabel) > panel.Max.X-2 {
+		labelX = lowX - chartLowRadius - 2 - labelWidth(lowLabel)
+	}
+	labelY := lowY - chartLowRadius - 2
+	drawLabel(img, labelX, labelY, lowLabel)
+}
+
+// formatChartPrice форматирует цену в центах для подписи на графике
+func formatChartPrice(price int) string {
+	return fmt.Sprintf("%.2f", float64(price)/100)
+}
+
+// fillRect закрашивает прямоугольник
+func fillRect(img *image.RGBA, rect image.Rectangle, c color.Color) {
+	draw.Draw(img, rect.Intersect(img.Bounds()), image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// drawFrame рисует рамку толщиной в один пиксель
+func drawFrame(img *image.RGBA, rect image.Rectangle, c color.Color) {
+	fillRect(img, image.Rect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Min.Y+1), c)
+	fillRect(img, image.Rect(rect.Min.X, rect.Max.Y, rect.Max.X, rect.Max.Y+1), c)
+	fillRect(img, image.Rect(rect.Min.X, rect.Min.Y, rect.Min.X+1, rect.Max.Y+1), c)
+	fillRect(img, image.Rect(rect.Max.X, rect.Min.Y, rect.Max.X+1, rect.Max.Y+1), c)
+}
+
+// fillCircle рисует закрашенный круг с центром (cx, cy)
+func fillCircle(img *image.RGBA, cx, cy, radius int, c color.Color) {
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			if dx*dx+dy*dy <= radius*radius {
+				img.Set(cx+dx, cy+dy, c)
+			}
+		}
+	}
+}
+
+// drawLabel выводит текст; (x, y) — левый край базовой линии
+func drawLabel(img *image.RGBA, x, y int, text string) {
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(chartText),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	drawer.DrawString(text)
+}
+
+// labelWidth возвращает ширину текста подписи в пикселях
+func labelWidth(text string) int {
+	return font.MeasureString(basicfont.Face7x13, text).Round()
+}
@@ -0,0 +1,18 @@
+package ratelimit
+
+import "context"
+
+// userKey — ключ контекста с ID пользователя, по которому считаются лимиты
+type userKey struct{}
+
+// WithUser сохраняет в ctx ID пользователя, от имени которого выполняется запрос.
+// Нужен ограничителям, которые стоят глубже обработчика (например, бюджет AI).
+func WithUser(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+// UserFromContext возвращает ID пользователя, сохраненный WithUser
+func UserFromContext(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(userKey{}).(int64)
+	return userID, ok
+}
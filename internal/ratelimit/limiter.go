@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+)
+
+// pruneInterval — как часто удалять корзины пользователей, которые давно не писали боту
+const pruneInterval = 10 * time.Minute
+
+// Limiter ограничивает частоту запросов по алгоритму token bucket отдельно для каждого ключа
+// (ID пользователя или чата). Пользователь может сделать burst запросов подряд,
+// после чего получает по одному новому запросу каждые refill.
+type Limiter struct {
+	clock  interfaces.Clock
+	burst  float64
+	refill time.Duration
+
+	mu        sync.Mutex
+	buckets   map[int64]*bucket
+	lastPrune time.Time
+}
+
+// bucket — токены одного пользователя
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewLimiter создает ограничитель: burst запросов подряд и один новый запрос каждые refill.
+// Если burst или refill не положительные, ограничение отключено.
+func NewLimiter(clock interfaces.Clock, burst int, refill time.Duration) *Limiter {
+	return &Limiter{
+		clock:   clock,
+		burst:   float64(burst),
+		refill:  refill,
+		buckets: make(map[int64]*bucket),
+	}
+}
+
+// Allow расходует один запрос ключа key.
+// Если запросов не осталось, возвращает false и время, через которое появится следующий.
+func (l *Limiter) Allow(key int64) (bool, time.Duration) {
+	if l == nil || l.burst <= 0 || l.refill <= 0 {
+		return true, 0
+	}
+
+	now := l.clock.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, updated: now}
+		l.buckets[key] = b
+	}
+
+	// Восстанавливаем токены за время, прошедшее с прошлого запроса
+	b.tokens = min(l.burst, b.tokens+float64(now.Sub(b.updated))/float64(l.refill))
+	b.updated = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) * float64(l.refill))
+	}
+
+	b.tokens--
+	return true, 0
+}
+
+// prune удаляет корзины, которые уже успели заполниться полностью:
+// для них новая корзина ничем не отличается от старой
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < pruneInterval {
+		return
+	}
+	l.lastPrune = now
+
+	full := time.Duration(l.burst * float64(l.refill))
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= full {
+			delete(l.buckets, key)
+		}
+	}
+}
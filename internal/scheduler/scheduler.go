@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+	"github.com/MaximVod/steambotgo/internal/logger"
+)
+
+// Task — периодическая фоновая задача.
+type Task func(ctx context.Context) error
+
+// Scheduler запускает фоновые задачи с заданным интервалом
+type Scheduler struct {
+	clock  interfaces.Clock
+	logger logger.Logger
+}
+
+// NewScheduler создает новый планировщик
+func NewScheduler(clock interfaces.Clock, logger logger.Logger) *Scheduler {
+	return &Scheduler{
+		clock:  clock,
+		logger: logger,
+	}
+}
+
+// Run выполняет задачу сразу и затем каждые interval, пока не отменен ctx.
+// Ошибка задачи логируется и не останавливает планировщик.
+// Блокирует вызывающую горутину — обычно запускается через go.
+func (s *Scheduler) Run(ctx context.Context, name string, interval time.Duration, task Task) {
+	s.logger.Info(ctx, "Запуск фоновой задачи", "task", name, "interval", interval)
+
+	for {
+		// У каждого запуска свой ID корреляции, чтобы отличать записи лога разных запусков
+		runCtx := logger.WithCorrelationID(ctx, logger.NewCorrelationID())
+		if err := task(runCtx); err != nil && ctx.Err() == nil {
+			s.logger.Error(runCtx, "Ошибка фоновой задачи", err, "task", name)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info(ctx, "Фоновая задача остановлена", "task", name)
+			return
+		case <-s.clock.After(interval):
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// checkTimeout — дедлайн на все проверки готовности одного запроса /readyz
+const checkTimeout = 3 * time.Second
+
+// Check проверяет зависимость бота (база данных, Steam).
+// Ошибка означает, что бот сейчас не может нормально обслуживать пользователей.
+type Check func(ctx context.Context) error
+
+// HealthHandler возвращает обработчик /healthz: отвечает 200, пока процесс обслуживает запросы.
+// Зависимости здесь не проверяются, чтобы сбой Steam не приводил к перезапуску бота.
+func HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+}
+
+// ReadyHandler возвращает обработчик /readyz: выполняет checks и отвечает 200, если все прошли,
+// иначе 503. В теле ответа — результат каждой проверки.
+func ReadyHandler(checks map[string]Check) http.Handler {
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		defer cancel()
+
+		code, status := http.StatusOK, "ok"
+		results := make(map[string]string, len(checks))
+		for _, name := range names {
+			if err := checks[name](ctx); err != nil {
+				code, status = http.StatusServiceUnavailable, "unavailable"
+				results[name] = err.Error()
+				continue
+			}
+			results[name] = "ok"
+		}
+
+		writeJSON(w, code, map[string]any{"status": status, "checks": results})
+	})
+}
+
+// writeJSON отправляет ответ в формате JSON
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/logger"
+)
+
+// shutdownTimeout — сколько ждать завершения активных запросов при остановке
+const shutdownTimeout = 10 * time.Second
+
+// Server — HTTP сервер бота (вебхук Telegram и служебные эндпоинты)
+type Server struct {
+	addr   string
+	mux    *http.ServeMux
+	logger logger.Logger
+}
+
+// NewServer создает сервер, который будет слушать addr (например, ":8080")
+func NewServer(addr string, logger logger.Logger) *Server {
+	return &Server{
+		addr:   addr,
+		mux:    http.NewServeMux(),
+		logger: logger,
+	}
+}
+
+// Handle регистрирует обработчик для pattern (синтаксис http.ServeMux)
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Run запускает сервер и блокируется до отмены ctx или ошибки запуска.
+// При отмене ctx сервер дожидается завершения активных запросов.
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("не удалось открыть порт %s: %w", s.addr, err)
+	}
+
+	httpServer := &http.Server{
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.Serve(listener)
+	}()
+	s.logger.Info(ctx, "HTTP сервер запущен", "addr", listener.Addr().String())
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("HTTP сервер остановлен: %w", err)
+	case <-ctx.Done():
+	}
+
+	// ctx уже отменен, поэтому для остановки нужен отдельный контекст
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("ошибка остановки HTTP сервера: %w", err)
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("HTTP сервер остановлен: %w", err)
+	}
+
+	s.logger.Info(ctx, "HTTP сервер остановлен")
+	return nil
+}
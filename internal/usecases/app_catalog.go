@@ -0,0 +1,62 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/MaximVod/steambotgo/internal/catalog"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+)
+
+// AppCatalogService хранит в памяти индекс всех приложений Steam для поиска названий
+// с опечатками, по-русски и по сокращениям — без платных запросов к AI.
+// Индекс обновляется через Refresh (обычно по расписанию); до первой загрузки поиск ничего не находит.
+type AppCatalogService struct {
+	provider interfaces.AppCatalogProvider // может быть nil — тогда каталог отключен
+
+	mu    sync.RWMutex
+	index *catalog.Index
+}
+
+// NewAppCatalogService создает сервис каталога
+func NewAppCatalogService(provider interfaces.AppCatalogProvider) *AppCatalogService {
+	return &AppCatalogService{
+		provider: provider,
+	}
+}
+
+// Refresh загружает список приложений и перестраивает индекс.
+// При ошибке сохраняется последний построенный индекс.
+func (s *AppCatalogService) Refresh(ctx context.Context) error {
+	if s.provider == nil {
+		return nil
+	}
+
+	apps, err := s.provider.GetApps(ctx)
+	if err != nil {
+		return fmt.Errorf("не удалось обновить каталог приложений Steam: %w", err)
+	}
+
+	// Индекс строится несколько секунд, поэтому поиск до замены идет по старому
+	index := catalog.NewIndex(apps)
+
+	s.mu.Lock()
+	s.index = index
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Search возвращает до limit приложений, похожих на запрос, по убыванию похожести
+func (s *AppCatalogService) Search(query string, limit int) []catalog.Match {
+	s.mu.RLock()
+	index := s.index
+	s.mu.RUnlock()
+
+	if index == nil {
+		return nil
+	}
+
+	return index.Search(query, limit)
+}
@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+)
+
+// CurrencyRatesService хранит актуальные курсы валют в памяти.
+// Курсы обновляются через Refresh (обычно по расписанию); если источник недоступен,
+// используются последние успешно загруженные курсы, а до первой загрузки — статическая таблица.
+type CurrencyRatesService struct {
+	provider interfaces.CurrencyRatesProvider // может быть nil — тогда только статическая таблица
+	fallback *entities.CurrencyRates
+
+	mu      sync.RWMutex
+	current *entities.CurrencyRates
+}
+
+// NewCurrencyRatesService создает сервис курсов.
+// staticRates — курсы к рублю из конфигурации (currency code -> rate to RUB).
+func NewCurrencyRatesService(provider interfaces.CurrencyRatesProvider, staticRates map[string]float64) *CurrencyRatesService {
+	return &CurrencyRatesService{
+		provider: provider,
+		fallback: &entities.CurrencyRates{
+			Base:  "RUB",
+			Rates: staticRates,
+		},
+	}
+}
+
+// Refresh загружает курсы из источника.
+// При ошибке сохраняются последние успешно загруженные курсы.
+func (s *CurrencyRatesService) Refresh(ctx context.Context) error {
+	if s.provider == nil {
+		return nil
+	}
+
+	rates, err := s.provider.GetRates(ctx)
+	if err != nil {
+		return fmt.Errorf("не удалось обновить курсы валют: %w", err)
+	}
+
+	s.mu.Lock()
+	s.current = rates
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Convert переводит сумму из одной валюты в другую.
+// Сначала используются загруженные курсы, затем статическая таблица.
+// Возвращает курсы, по которым выполнена конвертация (для показа даты курса),
+// или nil, если курс одной из валют неизвестен.
+func (s *CurrencyRatesService) Convert(amount float64, from, to string) (float64, *entities.CurrencyRates) {
+	s.mu.RLock()
+	current := s.current
+	s.mu.RUnlock()
+
+	if current != nil {
+		if converted, ok := current.Convert(amount, from, to); ok {
+			return converted, current
+		}
+	}
+
+	if converted, ok := s.fallback.Convert(amount, from, to); ok {
+		return converted, s.fallback
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,204 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+)
+
+// UnsupportedRegionError возвращается, если запрос просит магазин страны, цены которой бот не получает
+type UnsupportedRegionError struct {
+	CountryCode string
+}
+
+func (e *UnsupportedRegionError) Error() string {
+	return fmt.Sprintf("регион %s не поддерживается", e.CountryCode)
+}
+
+// DiscoveryService подбирает игры по запросу в свободной форме:
+// AI превращает запрос в фильтры и список подходящих игр, а каждая игра
+// проверяется по данным поиска Steam и цене в выбранном регионе.
+type DiscoveryService struct {
+	api                interfaces.SteamAPI
+	aiApi              interfaces.AiAPI
+	currencyRates      *CurrencyRatesService
+	supportedCountries []entities.Region
+	workers            int // сколько игр проверять в Steam одновременно
+	maxResults         int
+}
+
+func NewDiscoveryService(
+	api interfaces.SteamAPI,
+	aiApi interfaces.AiAPI,
+	rates *CurrencyRatesService,
+	countries []entities.Region,
+	workers int,
+	maxResults int,
+) *DiscoveryService {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &DiscoveryService{
+		api:                api,
+		aiApi:              aiApi,
+		currencyRates:      rates,
+		supportedCountries: countries,
+		workers:            workers,
+		maxResults:         maxResults,
+	}
+}
+
+// Discover подбирает игры по запросу пользователя.
+// Регион берется из запроса, а если он не указан — первый из выбранных в настройках.
+// Игры, которые не нашлись в Steam, не продаются в регионе или не проходят фильтры,
+// пропускаются; ошибка возвращается, только если из-за ошибок Steam не нашлось ни одной игры.
+func (s *DiscoveryService) Discover(ctx context.Context, request string, settings *entities.UserSettings) (*entities.DiscoveryResult, error) {
+	filters, err := s.aiApi.ParseDiscoveryQuery(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось разобрать запрос c помощью AI: %w", err)
+	}
+
+	region, err := s.region(filters.CountryCode, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entities.DiscoveryResult{
+		Query:   request,
+		Filters: filters,
+		Region:  region,
+	}
+
+	// Игры проверяются параллельно, но порядок AI (сначала самые подходящие) сохраняется
+	items := make([]*entities.SteamItem, len(filters.Titles))
+	errs := make([]error, len(filters.Titles))
+	semaphore := make(chan struct{}, s.workers)
+	var wg sync.WaitGroup
+	for i, title := range filters.Titles {
+		wg.Add(1)
+		go func(i int, title string) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+
+			items[i], errs[i] = s.checkTitle(ctx, title, filters, region)
+		}(i, title)
+	}
+	wg.Wait()
+
+	seen := make(map[int]bool, len(items))
+	for _, item := range items {
+		if item == nil || seen[item.ID] {
+			continue
+		}
+		seen[item.ID] = true
+		result.Items = append(result.Items, *item)
+		if s.maxResults > 0 && len(result.Items) == s.maxResults {
+			break
+		}
+	}
+
+	if len(result.Items) == 0 {
+		if err := errors.Join(errs...); err != nil {
+			return nil, fmt.Errorf("не удалось проверить игры в Steam: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// region возвращает магазин для поиска: страну из запроса или первый выбранный регион
+func (s *DiscoveryService) region(countryCode string, settings *entities.UserSettings) (entities.Region, error) {
+	if countryCode == "" {
+		if selected := SelectedRegions(s.supportedCountries, settings); len(selected) > 0 {
+			return selected[0], nil
+		}
+		return s.supportedCountries[0], nil
+	}
+
+	for _, region := range s.supportedCountries {
+		if strings.EqualFold(region.Code, countryCode) {
+			return region, nil
+		}
+	}
+	return entities.Region{}, &UnsupportedRegionError{CountryCode: countryCode}
+}
+
+// checkTitle находит игру в Steam и проверяет ее по фильтрам.
+// Возвращает nil без ошибки, если игра не найдена, не продается в регионе или не подходит.
+func (s *DiscoveryService) checkTitle(ctx context.Context, title string, filters *entities.DiscoveryFilters, region entities.Region) (*entities.SteamItem, error) {
+	// Поиск в американском магазине находит игру и ее платформы
+	game, err := s.api.SearchGameByQuery(ctx, title)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось найти игру %q: %w", title, err)
+	}
+	if game == nil || !matchesFeatures(game, filters) {
+		return nil, nil
+	}
+
+	// Цена в магазине выбранного региона
+	item, err := s.api.GetGamePricesByCountryCode(ctx, game.Name, region.Code, game.ID)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить цену игры %q в регионе %s: %w", game.Name, region.Code, err)
+	}
+	if item == nil || !s.withinPrice(item.Price, filters) {
+		return nil, nil
+	}
+
+	return item, nil
+}
+
+// matchesFeatures проверяет платформы и поддержку геймпада
+func matchesFeatures(game *entities.SteamItem, filters *entities.DiscoveryFilters) bool {
+	for _, platform := range filters.Platforms {
+		switch platform {
+		case "windows":
+			if !game.Platforms.Windows {
+				return false
+			}
+		case "mac":
+			if !game.Platforms.Mac {
+				return false
+			}
+		case "linux":
+			if !game.Platforms.Linux {
+				return false
+			}
+		}
+	}
+
+	// Поиск Steam указывает controller_support ("full", "partial") только для игр с поддержкой
+	return !filters.ControllerSupport || game.ControllerSupport != ""
+}
+
+// withinPrice проверяет, что цена не выше лимита. Бесплатные игры подходят всегда.
+// Если лимит в другой валюте, а курса нет, игра не подходит: показать ее
+// как подходящую по цене без проверки нельзя.
+func (s *DiscoveryService) withinPrice(price *entities.PriceInfo, filters *entities.DiscoveryFilters) bool {
+	if filters.MaxPrice <= 0 || price == nil {
+		return true
+	}
+
+	limit := filters.MaxPrice
+	if filters.MaxPriceCurrency != "" && !strings.EqualFold(filters.MaxPriceCurrency, price.Currency) {
+		converted, rates := s.currencyRates.Convert(limit, filters.MaxPriceCurrency, price.Currency)
+		if rates == nil {
+			return false
+		}
+		limit = converted
+	}
+
+	return float64(price.Final)/100 <= limit
+}
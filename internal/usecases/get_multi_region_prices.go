@@ -2,133 +2,291 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/MaximVod/steambotgo/internal/entities"
 	"github.com/MaximVod/steambotgo/internal/interfaces"
 )
 
+// maxCatalogCandidates — сколько похожих приложений из каталога проверять в Steam
+const maxCatalogCandidates = 3
+
 type MultiRegionPriceService struct {
 	api                interfaces.SteamAPI
 	aiApi              interfaces.AiAPI
-	supportedCountries map[string]string // country code -> flag emoji
-	currencyRates      map[string]float64
+	catalog            *AppCatalogService
+	supportedCountries []entities.Region
+	currencyRates      *CurrencyRatesService
+	regionWorkers      int           // сколько регионов запрашивать одновременно
+	regionTimeout      time.Duration // дедлайн на получение цены в одном регионе
 }
 
-func NewMultiRegionPriceService(api interfaces.SteamAPI, aiApi interfaces.AiAPI, countries map[string]string, rates map[string]float64) *MultiRegionPriceService {
+func NewMultiRegionPriceService(
+	api interfaces.SteamAPI,
+	aiApi interfaces.AiAPI,
+	catalog *AppCatalogService,
+	countries []entities.Region,
+	rates *CurrencyRatesService,
+	regionWorkers int,
+	regionTimeout time.Duration,
+) *MultiRegionPriceService {
+	if regionWorkers < 1 {
+		regionWorkers = 1
+	}
+
 	return &MultiRegionPriceService{
 		api:                api,
 		aiApi:              aiApi,
+		catalog:            catalog,
 		supportedCountries: countries,
 		currencyRates:      rates,
+		regionWorkers:      regionWorkers,
+		regionTimeout:      regionTimeout,
 	}
 }
 
-// GetMultiRegionPrices извлекает цены на игры из нескольких стран
-func (s *MultiRegionPriceService) GetMultiRegionPrices(ctx context.Context, query string) (*entities.MultiRegionPriceData, error) {
-	data := &entities.MultiRegionPriceData{}
-
-	var correctedQuery string
-
+// ResolveGame находит игру по запросу пользователя.
+// Сначала ищет в Steam, затем — в локальном каталоге приложений Steam (опечатки,
+// кириллица, сокращения), и только потом просит AI предложить названия.
+// Названия из каталога и от AI проверяются в Steam по очереди, пока одно не найдется.
+// Возвращает найденную игру (или nil) и примененное исправление запроса (nil, если запрос
+// нашелся как есть или ни одно исправление не помогло).
+func (s *MultiRegionPriceService) ResolveGame(ctx context.Context, query string) (*entities.SteamItem, *entities.QueryCorrection, error) {
 	// Сначала находим игру с помощью стандартного поиска (американский магазин)
 	game, err := s.api.SearchGameByQuery(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("не удалось найти игру: %w", err)
+		return nil, nil, fmt.Errorf("не удалось найти игру: %w", err)
+	}
+	if game != nil {
+		return game, nil, nil
+	}
+
+	// Каталог бесплатен, поэтому проверяется до платного запроса к AI.
+	// Ошибка проверки каталога не мешает попробовать AI и возвращается, только если
+	// игра так и не нашлась
+	game, correction, catalogErr := s.resolveFromCatalog(ctx, query)
+	if game != nil {
+		return game, correction, nil
 	}
 
 	// Если игра не найдена, пытаемся использовать AI для исправления запроса
-	if game == nil {
-		correctedQuery, err = s.aiApi.SearchGamesByUserQuery(ctx, query)
-		if err != nil {
-			return nil, fmt.Errorf("не удалось найти игру c помощью AI: %w", err)
+	candidates, err := s.aiApi.SearchGamesByUserQuery(ctx, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("не удалось найти игру c помощью AI: %w", err)
+	}
+
+	// Пробуем названия по очереди; ошибка Steam на одном кандидате не мешает проверить следующие
+	var searchErr error
+	for _, candidate := range candidates {
+		// Запрос пользователя Steam уже не нашел
+		if strings.EqualFold(candidate.Title, strings.TrimSpace(query)) {
+			continue
 		}
 
-		// Пытаемся найти игру с исправленным названием
-		game, err = s.api.SearchGameByQuery(ctx, correctedQuery)
+		game, err := s.api.SearchGameByQuery(ctx, candidate.Title)
 		if err != nil {
-			return nil, fmt.Errorf("не удалось найти игру после исправления AI: %w", err)
+			searchErr = err
+			continue
 		}
-
-		// Если и после AI ничего не найдено, возвращаем пустой результат
-		if game == nil {
-			return &entities.MultiRegionPriceData{
-				GameName: correctedQuery, // Используем исправленное название, даже если не нашли
-				Regions:  []*entities.RegionalPriceInfo{},
+		if game != nil && game.ID != 0 {
+			return game, &entities.QueryCorrection{
+				Query:      query,
+				Title:      candidate.Title,
+				Confidence: candidate.Confidence,
+				Source:     entities.CorrectionSourceAI,
 			}, nil
 		}
 	}
+	if searchErr != nil {
+		return nil, nil, fmt.Errorf("не удалось найти игру после исправления AI: %w", searchErr)
+	}
+	if catalogErr != nil {
+		return nil, nil, catalogErr
+	}
 
-	// Устанавливаем данные игры
-	data.GameName = game.Name
-	data.ID = game.ID
+	return nil, nil, nil
+}
 
-	// Получаем цены для каждой страны
-	// Пробуем разные варианты поиска для максимальной совместимости
-	for countryCode, flag := range s.supportedCountries {
-		var item *entities.SteamItem
+// resolveFromCatalog ищет запрос в каталоге приложений Steam.
+// Каталог содержит и то, что не продается в магазине (инструменты, удаленные игры),
+// поэтому приложение считается найденным, только если поиск Steam по его названию
+// возвращает этот же App ID.
+func (s *MultiRegionPriceService) resolveFromCatalog(ctx context.Context, query string) (*entities.SteamItem, *entities.QueryCorrection, error) {
+	if s.catalog == nil {
+		return nil, nil, nil
+	}
 
-		// Сначала пробуем по game.Name (точное название из Steam)
-		item, err = s.api.GetGamePricesByCountryCode(ctx, game.Name, countryCode, game.ID)
+	// Ошибка Steam на одном приложении не мешает проверить следующие
+	var searchErr error
+	for _, match := range s.catalog.Search(query, maxCatalogCandidates) {
+		items, err := s.api.SearchGamesByName(ctx, match.App.Name)
 		if err != nil {
-			// Пропускаем эту страну, если произошла ошибка
+			searchErr = err
 			continue
 		}
 
-		// Если не нашли по game.Name и есть исправленный AI запрос, пробуем его
-		if item == nil && correctedQuery != "" && correctedQuery != game.Name {
-			item, err = s.api.GetGamePricesByCountryCode(ctx, correctedQuery, countryCode, game.ID)
-			if err != nil {
-				// Пропускаем эту страну, если произошла ошибка
-				continue
+		for i := range items {
+			if items[i].ID == match.App.ID {
+				return &items[i], &entities.QueryCorrection{
+					Query:      query,
+					Title:      items[i].Name,
+					Confidence: match.Score,
+					Source:     entities.CorrectionSourceCatalog,
+				}, nil
 			}
 		}
+	}
+	if searchErr != nil {
+		return nil, nil, fmt.Errorf("не удалось проверить игру из каталога: %w", searchErr)
+	}
 
-		// Если все еще не нашли, пробуем оригинальный запрос
-		if item == nil && query != game.Name && query != correctedQuery {
-			item, err = s.api.GetGamePricesByCountryCode(ctx, query, countryCode, game.ID)
-			if err != nil {
-				// Пропускаем эту страну, если произошла ошибка
-				continue
-			}
-		}
+	return nil, nil, nil
+}
 
-		// Если нашли игру (с нужным ID), добавляем в результат
-		if item != nil {
-			// Рассчитываем значение в рублях
-			var convertedRub float64
-			if item.Price != nil {
-				convertedRub = s.convertPriceToRubles(float64(item.Price.Final)/100, item.Price.Currency)
+// GetMultiRegionPrices извлекает цены на игры из нескольких стран.
+// settings задают регионы и валюту конвертации; nil — все регионы и рубли.
+func (s *MultiRegionPriceService) GetMultiRegionPrices(ctx context.Context, query string, settings *entities.UserSettings) (*entities.MultiRegionPriceData, error) {
+	game, correction, err := s.ResolveGame(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Если и после AI ничего не найдено, возвращаем пустой результат
+	if game == nil {
+		return &entities.MultiRegionPriceData{
+			GameName: query,
+			Regions:  []*entities.RegionalPriceInfo{},
+		}, nil
+	}
+
+	data := s.GetMultiRegionPricesForGame(ctx, game, settings)
+	data.Correction = correction
+	return data, nil
+}
+
+// GetMultiRegionPricesForGame извлекает цены уже найденной игры из нескольких стран.
+// Для запроса цен достаточно ID; остальные поля game копируются в региональные результаты.
+func (s *MultiRegionPriceService) GetMultiRegionPricesForGame(ctx context.Context, game *entities.SteamItem, settings *entities.UserSettings) *entities.MultiRegionPriceData {
+	homeCurrency := DefaultHomeCurrency
+	if settings != nil && settings.HomeCurrency != "" {
+		homeCurrency = settings.HomeCurrency
+	}
+
+	// Устанавливаем данные игры
+	data := &entities.MultiRegionPriceData{
+		GameName: game.Name,
+		ID:       game.ID,
+	}
+
+	// Получаем цены для каждой страны параллельно, но не больше regionWorkers запросов одновременно.
+	// Ошибка или таймаут в одном регионе не мешают остальным: регион попадает
+	// в результат со статусом ошибки, а не пропускается
+	// Регионы добавляются в порядке из конфигурации, чтобы результат был детерминированным
+	countries := SelectedRegions(s.supportedCountries, settings)
+	data.Regions = make([]*entities.RegionalPriceInfo, 0, len(countries))
+	for _, country := range countries {
+		data.Regions = append(data.Regions, &entities.RegionalPriceInfo{
+			CountryCode:       country.Code,
+			CountryName:       country.Name,
+			CountryFlag:       country.Flag,
+			ConvertedCurrency: homeCurrency,
+		})
+	}
+
+	semaphore := make(chan struct{}, s.regionWorkers)
+	var (
+		wg      sync.WaitGroup
+		ratesMu sync.Mutex
+	)
+	for _, region := range data.Regions {
+		wg.Add(1)
+		go func(region *entities.RegionalPriceInfo) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				region.Status = entities.RegionStatusError
+				region.Err = ctx.Err()
+				return
 			}
 
-			regionalPrice := &entities.RegionalPriceInfo{
-				CountryCode:  countryCode,
-				CountryFlag:  flag,
-				Item:         item,
-				ConvertedRub: convertedRub,
+			rates := s.fillRegionPrice(ctx, game, region)
+
+			// Запоминаем дату курса, если конвертация шла по загруженным (не статическим) курсам
+			if rates != nil && !rates.Date.IsZero() {
+				ratesMu.Lock()
+				data.RatesSource = rates.Source
+				data.RatesDate = rates.Date
+				ratesMu.Unlock()
 			}
+		}(region)
+	}
+	wg.Wait()
 
-			data.Regions = append(data.Regions, regionalPrice)
+	return data
+}
+
+// fillRegionPrice получает цену игры в регионе по App ID (appdetails) и заполняет region.
+// Цена запрашивается по ID, а не повторным поиском по названию — локализованная выдача
+// поиска в разных странах может отличаться и не содержать нужную игру.
+// Возвращает курсы, по которым цена переведена в валюту пользователя (nil, если конвертации не было).
+func (s *MultiRegionPriceService) fillRegionPrice(ctx context.Context, game *entities.SteamItem, region *entities.RegionalPriceInfo) *entities.CurrencyRates {
+	if s.regionTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.regionTimeout)
+		defer cancel()
+	}
+
+	prices, err := s.api.GetAppPrices(ctx, []int{game.ID}, region.CountryCode)
+	if err != nil {
+		region.Status = entities.RegionStatusError
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			region.Status = entities.RegionStatusTimeout
 		}
+		region.Err = err
+		return nil
 	}
 
-	return data, nil
+	price, available := prices[game.ID]
+	if !available {
+		// Игра недоступна в магазине этой страны
+		region.Status = entities.RegionStatusUnavailable
+		return nil
+	}
+
+	// Копируем игру из поиска и подставляем региональную цену
+	item := *game
+	item.Price = price
+
+	region.Status = entities.RegionStatusOK
+	region.Item = &item
+	if item.Price == nil {
+		return nil
+	}
+
+	// Рассчитываем значение в валюте пользователя
+	var rates *entities.CurrencyRates
+	region.Converted, rates = s.convertPrice(float64(item.Price.Final)/100, item.Price.Currency, region.ConvertedCurrency)
+	return rates
 }
 
-// convertPriceToRubles обеспечивает приблизительную конвертацию в рубли на основе валюты
-func (s *MultiRegionPriceService) convertPriceToRubles(price float64, currency string) float64 {
-	// Примечание: API поиска Steam возвращает данные о ценах, которые могут не полностью отражать
-	// региональные различия, так как ограничены используемым нами конечным пунктом.
-	// Для получения точных региональных цен нам нужно использовать API обзора цен Steam для каждого конкретного ID приложения.
-
-	// Используем курсы из конфигурации
-	rate, exists := s.currencyRates[currency]
-	if !exists {
-		// Для неизвестных валют используем курс USD по умолчанию
-		rate = s.currencyRates["USD"]
-		if rate == 0 {
-			rate = 90 // Fallback значение
-		}
+// convertPrice обеспечивает приблизительную конвертацию цены в валюту пользователя.
+// Возвращает курсы, по которым выполнена конвертация.
+func (s *MultiRegionPriceService) convertPrice(price float64, currency, homeCurrency string) (float64, *entities.CurrencyRates) {
+	if converted, rates := s.currencyRates.Convert(price, currency, homeCurrency); rates != nil {
+		return converted, rates
+	}
+
+	// Для неизвестных валют используем курс USD по умолчанию
+	if converted, rates := s.currencyRates.Convert(price, "USD", homeCurrency); rates != nil {
+		return converted, rates
 	}
 
-	return price * rate
+	return 0, nil // Конвертация невозможна — цена покажется без пересчета
 }
@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+)
+
+// ErrNoPriceHistory возвращается, если цены игры еще ни разу не сохранялись.
+// История копится только для отслеживаемых игр (/track).
+var ErrNoPriceHistory = errors.New("история цен не найдена")
+
+type PriceHistoryService struct {
+	snapshots          interfaces.PriceSnapshotRepository
+	games              interfaces.GameRepository
+	priceService       *MultiRegionPriceService
+	supportedCountries []entities.Region
+}
+
+func NewPriceHistoryService(
+	snapshots interfaces.PriceSnapshotRepository,
+	games interfaces.GameRepository,
+	priceService *MultiRegionPriceService,
+	countries []entities.Region,
+) *PriceHistoryService {
+	return &PriceHistoryService{
+		snapshots:          snapshots,
+		games:              games,
+		priceService:       priceService,
+		supportedCountries: countries,
+	}
+}
+
+// GetHistory возвращает историю цен игры в регионах из настроек пользователя.
+// Игру можно указать так же, как в /untrack (App ID или название из списка пользователя),
+// а если в списке её нет — она ищется в Steam.
+func (s *PriceHistoryService) GetHistory(ctx context.Context, userChatID int64, query string, settings *entities.UserSettings) (*entities.PriceHistory, error) {
+	history, err := s.resolveGame(ctx, userChatID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.snapshots.GetSnapshots(ctx, history.GameID)
+	if err != nil {
+		return nil, err
+	}
+
+	byCountry := make(map[string][]entities.PriceSnapshot)
+	for _, snapshot := range snapshots {
+		byCountry[snapshot.CountryCode] = append(byCountry[snapshot.CountryCode], snapshot)
+	}
+
+	// Регионы в порядке из конфигурации; снимки без страны (до миграции 002) пропускаются
+	for _, country := range SelectedRegions(s.supportedCountries, settings) {
+		regionSnapshots := sameCurrencySnapshots(byCountry[country.Code])
+		if len(regionSnapshots) == 0 {
+			continue
+		}
+
+		history.Regions = append(history.Regions, entities.RegionPriceHistory{
+			CountryCode: country.Code,
+			CountryFlag: country.Flag,
+			Currency:    regionSnapshots[0].Currency,
+			Snapshots:   regionSnapshots,
+		})
+	}
+
+	if len(history.Regions) == 0 {
+		return history, ErrNoPriceHistory
+	}
+
+	return history, nil
+}
+
+// resolveGame находит игру сначала в списке отслеживаемых пользователем, затем в Steam
+func (s *PriceHistoryService) resolveGame(ctx context.Context, userChatID int64, query string) (*entities.PriceHistory, error) {
+	games, err := s.games.GetTrackedGamesByUser(ctx, userChatID)
+	if err != nil {
+		return nil, err
+	}
+	if tracked := findTrackedGame(games, query); tracked != nil {
+		return &entities.PriceHistory{GameID: tracked.GameID, GameName: tracked.GameName}, nil
+	}
+
+	game, _, err := s.priceService.ResolveGame(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if game == nil {
+		return nil, ErrGameNotFound
+	}
+
+	return &entities.PriceHistory{GameID: int64(game.ID), GameName: game.Name}, nil
+}
+
+// sameCurrencySnapshots оставляет снимки в валюте последней проверки.
+// Если магазин региона сменил валюту, старые цены на одном графике с новыми несравнимы.
+func sameCurrencySnapshots(snapshots []entities.PriceSnapshot) []entities.PriceSnapshot {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	currency := snapshots[len(snapshots)-1].Currency
+	var result []entities.PriceSnapshot
+	for _, snapshot := range snapshots {
+		if snapshot.Currency == currency {
+			result = append(result, snapshot)
+		}
+	}
+	return result
+}
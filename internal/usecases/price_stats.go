@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+)
+
+const (
+	// minStatsSnapshots — сколько снимков цены нужно, чтобы судить об истории.
+	// По одной-двум проверкам любая цена выглядит историческим минимумом.
+	minStatsSnapshots = 4
+
+	// nearLowMargin — насколько цена может быть выше минимума, чтобы считаться близкой к нему
+	nearLowMargin = 0.10
+)
+
+// PriceStatsService оценивает текущие цены по истории цен (price_snapshots).
+type PriceStatsService struct {
+	snapshots interfaces.PriceSnapshotRepository
+	clock     interfaces.Clock
+}
+
+func NewPriceStatsService(snapshots interfaces.PriceSnapshotRepository, clock interfaces.Clock) *PriceStatsService {
+	return &PriceStatsService{
+		snapshots: snapshots,
+		clock:     clock,
+	}
+}
+
+// AddStats дополняет регионы с ценой историческим минимумом и оценкой текущей цены.
+// История копится только для отслеживаемых игр, у остальных Stats остаются nil.
+func (s *PriceStatsService) AddStats(ctx context.Context, data *entities.MultiRegionPriceData) error {
+	if data.ID == 0 {
+		return nil
+	}
+
+	snapshots, err := s.snapshots.GetSnapshots(ctx, int64(data.ID))
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	byCountry := make(map[string][]entities.PriceSnapshot)
+	for _, snapshot := range snapshots {
+		byCountry[snapshot.CountryCode] = append(byCountry[snapshot.CountryCode], snapshot)
+	}
+
+	now := s.clock.Now()
+	for _, region := range data.Regions {
+		if region.Status != entities.RegionStatusOK || region.Item.Price == nil {
+			continue
+		}
+		region.Stats = priceStats(byCountry[region.CountryCode], region.Item.Price.Final, region.Item.Price.Currency, now)
+	}
+
+	return nil
+}
+
+// priceStats считает минимум и среднее по снимкам в валюте currency и оценивает цену current,
+// полученную в момент now. Возвращает nil, если снимков для оценки недостаточно.
+func priceStats(snapshots []entities.PriceSnapshot, current int, currency string, now time.Time) *entities.PriceStats {
+	var (
+		stats   *entities.PriceStats
+		highest int
+		count   int
+		sum     int64
+	)
+	for _, snapshot := range snapshots {
+		// Цены в другой валюте (магазин региона сменил валюту) несравнимы с текущей
+		if snapshot.Currency != currency {
+			continue
+		}
+		if stats == nil || snapshot.Price < stats.LowestPrice {
+			stats = &entities.PriceStats{LowestPrice: snapshot.Price, LowestAt: snapshot.CheckedAt}
+		}
+		highest = max(highest, snapshot.Price)
+		count++
+		sum += int64(snapshot.Price)
+	}
+	if count < minStatsSnapshots {
+		return nil
+	}
+
+	stats.AveragePrice = int(sum / int64(count))
+
+	// Если цена ни разу не менялась, она одновременно минимум и максимум:
+	// «исторический минимум» для игры без скидок ничего не сообщает
+	varied := highest > stats.LowestPrice
+
+	switch {
+	case current < stats.LowestPrice || (varied && current == stats.LowestPrice):
+		stats.Verdict = entities.PriceVerdictAllTimeLow
+	case varied && current < highest && float64(current) <= float64(stats.LowestPrice)*(1+nearLowMargin):
+		stats.Verdict = entities.PriceVerdictNearLow
+	case current > stats.AveragePrice:
+		stats.Verdict = entities.PriceVerdictAboveAverage
+	}
+
+	// Текущая цена может быть ниже всех сохраненных: тогда минимум — она сама,
+	// иначе карточка покажет «минимум» выше цены рядом с оценкой «исторический минимум»
+	if current < stats.LowestPrice {
+		stats.LowestPrice = current
+		stats.LowestAt = now
+	}
+
+	return stats
+}
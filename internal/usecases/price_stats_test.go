@@ -0,0 +1,132 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+)
+
+func TestPriceStats(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+
+	// history строит снимки в рублях: первая цена — самая старая
+	history := func(prices ...int) []entities.PriceSnapshot {
+		snapshots := make([]entities.PriceSnapshot, 0, len(prices))
+		for i, price := range prices {
+			snapshots = append(snapshots, entities.PriceSnapshot{
+				CountryCode: "RU",
+				Price:       price,
+				Currency:    "RUB",
+				CheckedAt:   day(len(prices) - i),
+			})
+		}
+		return snapshots
+	}
+
+	tests := []struct {
+		name      string
+		snapshots []entities.PriceSnapshot
+		current   int
+		want      *entities.PriceStats
+	}{
+		{
+			name:      "недостаточно снимков",
+			snapshots: history(1000, 1000, 1000),
+			current:   1000,
+			want:      nil,
+		},
+		{
+			name:      "постоянная цена без скидок",
+			snapshots: history(1000, 1000, 1000, 1000),
+			current:   1000,
+			want:      &entities.PriceStats{LowestPrice: 1000, LowestAt: day(4), AveragePrice: 1000},
+		},
+		{
+			name:      "цена выросла после постоянной",
+			snapshots: history(1000, 1000, 1000, 1000),
+			current:   1200,
+			want:      &entities.PriceStats{LowestPrice: 1000, LowestAt: day(4), AveragePrice: 1000, Verdict: entities.PriceVerdictAboveAverage},
+		},
+		{
+			name:      "новый минимум ниже всех снимков",
+			snapshots: history(1000, 1000, 800, 1000),
+			current:   500,
+			want:      &entities.PriceStats{LowestPrice: 500, LowestAt: now, AveragePrice: 950, Verdict: entities.PriceVerdictAllTimeLow},
+		},
+		{
+			name:      "новый минимум у постоянной цены",
+			snapshots: history(1000, 1000, 1000, 1000),
+			current:   700,
+			want:      &entities.PriceStats{LowestPrice: 700, LowestAt: now, AveragePrice: 1000, Verdict: entities.PriceVerdictAllTimeLow},
+		},
+		{
+			name:      "повтор прежнего минимума",
+			snapshots: history(1000, 500, 1000, 1000),
+			current:   500,
+			want:      &entities.PriceStats{LowestPrice: 500, LowestAt: day(3), AveragePrice: 875, Verdict: entities.PriceVerdictAllTimeLow},
+		},
+		{
+			name:      "близко к минимуму",
+			snapshots: history(1000, 500, 1000, 1000),
+			current:   540,
+			want:      &entities.PriceStats{LowestPrice: 500, LowestAt: day(3), AveragePrice: 875, Verdict: entities.PriceVerdictNearLow},
+		},
+		{
+			name:      "максимум не считается близким к минимуму",
+			snapshots: history(1000, 1050, 1000, 1050),
+			current:   1050,
+			want:      &entities.PriceStats{LowestPrice: 1000, LowestAt: day(4), AveragePrice: 1025, Verdict: entities.PriceVerdictAboveAverage},
+		},
+		{
+			name:      "выше средней",
+			snapshots: history(1000, 500, 1000, 1000),
+			current:   1000,
+			want:      &entities.PriceStats{LowestPrice: 500, LowestAt: day(3), AveragePrice: 875, Verdict: entities.PriceVerdictAboveAverage},
+		},
+		{
+			name:      "обычная цена между минимумом и средней",
+			snapshots: history(1000, 500, 1000, 1000),
+			current:   700,
+			want:      &entities.PriceStats{LowestPrice: 500, LowestAt: day(3), AveragePrice: 875},
+		},
+		{
+			name: "снимки в другой валюте не учитываются",
+			snapshots: append(history(1000, 1000, 1000, 1000),
+				entities.PriceSnapshot{CountryCode: "RU", Price: 10, Currency: "USD", CheckedAt: day(10)},
+				entities.PriceSnapshot{CountryCode: "RU", Price: 5, Currency: "USD", CheckedAt: day(9)},
+			),
+			current: 1000,
+			want:    &entities.PriceStats{LowestPrice: 1000, LowestAt: day(4), AveragePrice: 1000},
+		},
+		{
+			name: "мало снимков в текущей валюте",
+			snapshots: []entities.PriceSnapshot{
+				{Price: 10, Currency: "USD", CheckedAt: day(5)},
+				{Price: 10, Currency: "USD", CheckedAt: day(4)},
+				{Price: 1000, Currency: "RUB", CheckedAt: day(3)},
+				{Price: 800, Currency: "RUB", CheckedAt: day(2)},
+				{Price: 1000, Currency: "RUB", CheckedAt: day(1)},
+			},
+			current: 800,
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := priceStats(tt.snapshots, tt.current, "RUB", now)
+
+			if tt.want == nil || got == nil {
+				if tt.want != got {
+					t.Fatalf("priceStats() = %+v, want %+v", got, tt.want)
+				}
+				return
+			}
+			if *got != *tt.want {
+				t.Errorf("priceStats() = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
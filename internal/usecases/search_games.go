@@ -12,23 +12,24 @@ import (
 type SearchGamesService struct {
 	steamAPI interfaces.SteamAPI
 	aiAPI    interfaces.AiAPI
+	logger   logger.Logger
 }
 
-func NewSearchGamesService(api interfaces.SteamAPI, aiAPI interfaces.AiAPI) *SearchGamesService {
+func NewSearchGamesService(api interfaces.SteamAPI, aiAPI interfaces.AiAPI, logger logger.Logger) *SearchGamesService {
 	return &SearchGamesService{
 		steamAPI: api,
 		aiAPI:    aiAPI,
+		logger:   logger,
 	}
 }
 
 // FetchGames ищет игры по запросу и возвращает список найденных игр.
 func (s *SearchGamesService) FetchGames(ctx context.Context, query string) ([]entities.SteamItem, error) {
 	items, err := s.steamAPI.SearchGamesByName(ctx, query)
-	appLogger := logger.New()
-	appLogger.Info("Result Exist " + fmt.Sprintf("SearchGamesService ", query))
 	if err != nil {
 		return nil, fmt.Errorf("не удалось найти игры: %w", err)
 	}
+	s.logger.Debug(ctx, "Поиск игр", "query", query, "count", len(items))
 	return items, nil
 }
 
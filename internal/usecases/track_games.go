@@ -0,0 +1,106 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+)
+
+// ErrGameNotFound возвращается, если игру не удалось найти ни в Steam, ни с помощью AI.
+var ErrGameNotFound = errors.New("игра не найдена")
+
+type TrackGamesService struct {
+	repo         interfaces.GameRepository
+	priceService *MultiRegionPriceService
+}
+
+func NewTrackGamesService(repo interfaces.GameRepository, priceService *MultiRegionPriceService) *TrackGamesService {
+	return &TrackGamesService{
+		repo:         repo,
+		priceService: priceService,
+	}
+}
+
+// Track находит игру по запросу и добавляет её в список отслеживаемых пользователем.
+func (s *TrackGamesService) Track(ctx context.Context, userChatID int64, query string) (*entities.TrackedGame, error) {
+	game, _, err := s.priceService.ResolveGame(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if game == nil {
+		return nil, ErrGameNotFound
+	}
+
+	tracked := &entities.TrackedGame{
+		GameID:     int64(game.ID),
+		GameName:   game.Name,
+		UserChatID: userChatID,
+	}
+	if err := s.repo.SaveTrackedGame(ctx, tracked); err != nil {
+		return tracked, err
+	}
+
+	return tracked, nil
+}
+
+// Untrack удаляет игру из списка отслеживаемых.
+// Игру можно указать Steam App ID или частью названия из списка пользователя.
+// Возвращает удаленную игру или nil, если подходящей игры в списке нет.
+func (s *TrackGamesService) Untrack(ctx context.Context, userChatID int64, query string) (*entities.TrackedGame, error) {
+	games, err := s.repo.GetTrackedGamesByUser(ctx, userChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	game := findTrackedGame(games, query)
+	if game == nil {
+		return nil, nil
+	}
+
+	deleted, err := s.repo.DeleteTrackedGame(ctx, userChatID, game.GameID)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, nil
+	}
+
+	return game, nil
+}
+
+// ListTracked возвращает игры, которые отслеживает пользователь.
+func (s *TrackGamesService) ListTracked(ctx context.Context, userChatID int64) ([]entities.TrackedGame, error) {
+	return s.repo.GetTrackedGamesByUser(ctx, userChatID)
+}
+
+// findTrackedGame ищет игру в списке по App ID или по названию.
+// Точное совпадение названия приоритетнее частичного.
+func findTrackedGame(games []entities.TrackedGame, query string) *entities.TrackedGame {
+	query = strings.TrimSpace(query)
+
+	if id, err := strconv.ParseInt(query, 10, 64); err == nil {
+		for i := range games {
+			if games[i].GameID == id {
+				return &games[i]
+			}
+		}
+	}
+
+	lowerQuery := strings.ToLower(query)
+	for i := range games {
+		if strings.ToLower(games[i].GameName) == lowerQuery {
+			return &games[i]
+		}
+	}
+	for i := range games {
+		if strings.Contains(strings.ToLower(games[i].GameName), lowerQuery) {
+			return &games[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/i18n"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+)
+
+// DefaultHomeCurrency — валюта конвертации, пока чат не выбрал свою
+const DefaultHomeCurrency = "RUB"
+
+type UserSettingsService struct {
+	repo       interfaces.UserSettingsRepository
+	regions    []entities.Region
+	currencies []string
+}
+
+// NewUserSettingsService создает сервис настроек.
+// regions — все доступные регионы, currencies — валюты, которые можно выбрать для конвертации.
+func NewUserSettingsService(repo interfaces.UserSettingsRepository, regions []entities.Region, currencies []string) *UserSettingsService {
+	return &UserSettingsService{
+		repo:       repo,
+		regions:    regions,
+		currencies: currencies,
+	}
+}
+
+// Regions возвращает все доступные регионы.
+func (s *UserSettingsService) Regions() []entities.Region {
+	return s.regions
+}
+
+// Currencies возвращает валюты, которые можно выбрать для конвертации.
+func (s *UserSettingsService) Currencies() []string {
+	return s.currencies
+}
+
+// Get возвращает настройки чата, а если их нет — настройки по умолчанию.
+// Регионы, которых больше нет в конфигурации, отбрасываются.
+func (s *UserSettingsService) Get(ctx context.Context, chatID int64) (*entities.UserSettings, error) {
+	settings, err := s.repo.GetSettings(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return s.defaults(chatID), nil
+	}
+
+	var regions []string
+	for _, region := range s.regions {
+		if settings.HasRegion(region.Code) {
+			regions = append(regions, region.Code)
+		}
+	}
+	if len(regions) == 0 {
+		regions = s.defaults(chatID).Regions
+	}
+	settings.Regions = regions
+
+	return settings, nil
+}
+
+// ToggleRegion включает или выключает регион.
+// Последний выбранный регион выключить нельзя — иначе цены будет негде показать.
+func (s *UserSettingsService) ToggleRegion(ctx context.Context, chatID int64, code string) (*entities.UserSettings, error) {
+	if !slices.ContainsFunc(s.regions, func(region entities.Region) bool { return region.Code == code }) {
+		return nil, fmt.Errorf("неизвестный регион: %s", code)
+	}
+
+	settings, err := s.Get(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.HasRegion(code) {
+		if len(settings.Regions) == 1 {
+			return settings, nil
+		}
+		settings.Regions = slices.DeleteFunc(settings.Regions, func(region string) bool { return region == code })
+	} else {
+		settings.Regions = append(settings.Regions, code)
+	}
+
+	return settings, s.save(ctx, settings)
+}
+
+// SetHomeCurrency меняет валюту конвертации.
+func (s *UserSettingsService) SetHomeCurrency(ctx context.Context, chatID int64, currency string) (*entities.UserSettings, error) {
+	if !slices.Contains(s.currencies, currency) {
+		return nil, fmt.Errorf("неподдерживаемая валюта: %s", currency)
+	}
+
+	settings, err := s.Get(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	settings.HomeCurrency = currency
+
+	return settings, s.save(ctx, settings)
+}
+
+// SetLanguage меняет язык сообщений.
+// Пустой language — язык берется из настроек Telegram пользователя.
+func (s *UserSettingsService) SetLanguage(ctx context.Context, chatID int64, language string) (*entities.UserSettings, error) {
+	if language != "" && !i18n.IsSupported(language) {
+		return nil, fmt.Errorf("неподдерживаемый язык: %s", language)
+	}
+
+	settings, err := s.Get(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	settings.Language = language
+
+	return settings, s.save(ctx, settings)
+}
+
+// SelectedRegions возвращает выбранные регионы в порядке из конфигурации.
+// Для nil-настроек возвращает все регионы.
+func SelectedRegions(all []entities.Region, settings *entities.UserSettings) []entities.Region {
+	if settings == nil {
+		return all
+	}
+
+	var selected []entities.Region
+	for _, region := range all {
+		if settings.HasRegion(region.Code) {
+			selected = append(selected, region)
+		}
+	}
+	return selected
+}
+
+func (s *UserSettingsService) save(ctx context.Context, settings *entities.UserSettings) error {
+	// Храним регионы в порядке из конфигурации, а не в порядке нажатия кнопок
+	settings.Regions = regionCodes(SelectedRegions(s.regions, settings))
+	return s.repo.SaveSettings(ctx, settings)
+}
+
+// defaults возвращает настройки по умолчанию: все регионы, рубли, язык из Telegram
+func (s *UserSettingsService) defaults(chatID int64) *entities.UserSettings {
+	return &entities.UserSettings{
+		ChatID:       chatID,
+		Regions:      regionCodes(s.regions),
+		HomeCurrency: DefaultHomeCurrency,
+	}
+}
+
+// regionCodes возвращает коды регионов
+func regionCodes(regions []entities.Region) []string {
+	codes := make([]string, 0, len(regions))
+	for _, region := range regions {
+		codes = append(codes, region.Code)
+	}
+	return codes
+}
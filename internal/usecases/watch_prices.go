@@ -0,0 +1,211 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MaximVod/steambotgo/internal/entities"
+	"github.com/MaximVod/steambotgo/internal/interfaces"
+	"github.com/MaximVod/steambotgo/internal/logger"
+)
+
+// PriceWatcherService проверяет цены отслеживаемых игр и уведомляет подписчиков о снижении.
+type PriceWatcherService struct {
+	api                interfaces.SteamAPI
+	games              interfaces.GameRepository
+	snapshots          interfaces.PriceSnapshotRepository
+	notifier           interfaces.PriceDropNotifier
+	clock              interfaces.Clock
+	logger             logger.Logger
+	supportedCountries []entities.Region
+	recheckAfter       time.Duration
+	batchSize          int
+}
+
+func NewPriceWatcherService(
+	api interfaces.SteamAPI,
+	games interfaces.GameRepository,
+	snapshots interfaces.PriceSnapshotRepository,
+	notifier interfaces.PriceDropNotifier,
+	clock interfaces.Clock,
+	logger logger.Logger,
+	countries []entities.Region,
+	recheckAfter time.Duration,
+	batchSize int,
+) *PriceWatcherService {
+	return &PriceWatcherService{
+		api:                api,
+		games:              games,
+		snapshots:          snapshots,
+		notifier:           notifier,
+		clock:              clock,
+		logger:             logger,
+		supportedCountries: countries,
+		recheckAfter:       recheckAfter,
+		batchSize:          batchSize,
+	}
+}
+
+// CheckDueGames проверяет цены игр, которые давно не проверялись.
+// Возвращает количество проверенных игр.
+func (s *PriceWatcherService) CheckDueGames(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+
+	due, err := s.games.GetGamesDueForCheck(ctx, now.Add(-s.recheckAfter), s.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	// В tracked_games одна строка на пару (игра, пользователь) — проверяем каждую игру один раз
+	var games []entities.TrackedGame
+	seen := make(map[int64]bool, len(due))
+	for _, game := range due {
+		if !seen[game.GameID] {
+			seen[game.GameID] = true
+			games = append(games, game)
+		}
+	}
+	if len(games) == 0 {
+		return 0, nil
+	}
+
+	pricesByCountry := s.fetchPrices(ctx, games)
+
+	for i, game := range games {
+		// Останавливаемся между играми, если приложение завершается
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+
+		if err := s.checkGame(ctx, game, pricesByCountry); err != nil {
+			s.logger.Error(ctx, "Ошибка проверки цены игры", err, "game", game.GameName)
+		}
+	}
+
+	return len(games), nil
+}
+
+// fetchPrices получает цены всех игр пачкой — один запрос на регион.
+// Регионы, в которых запрос не удался, в результат не попадают.
+func (s *PriceWatcherService) fetchPrices(ctx context.Context, games []entities.TrackedGame) map[string]map[int]*entities.PriceInfo {
+	appIDs := make([]int, 0, len(games))
+	for _, game := range games {
+		appIDs = append(appIDs, int(game.GameID))
+	}
+
+	pricesByCountry := make(map[string]map[int]*entities.PriceInfo, len(s.supportedCountries))
+	for _, country := range s.supportedCountries {
+		prices, err := s.api.GetAppPrices(ctx, appIDs, country.Code)
+		if err != nil {
+			// Ошибка в одном регионе не мешает проверить остальные
+			s.logger.Error(ctx, "Ошибка получения цен", err, "country", country.Code)
+			continue
+		}
+		pricesByCountry[country.Code] = prices
+	}
+
+	return pricesByCountry
+}
+
+// checkGame сохраняет текущие цены игры во всех регионах и уведомляет подписчиков о снижении
+func (s *PriceWatcherService) checkGame(ctx context.Context, game entities.TrackedGame, pricesByCountry map[string]map[int]*entities.PriceInfo) error {
+	now := s.clock.Now()
+	drop := &entities.PriceDrop{
+		GameID:   game.GameID,
+		GameName: game.GameName,
+	}
+
+	// checked — хотя бы в одном регионе Steam ответил: есть цена, игра бесплатна или не продается
+	checked := false
+
+	// Обходим регионы в порядке из конфигурации, чтобы уведомление было в том же порядке, что и /find
+	for _, country := range s.supportedCountries {
+		countryCode := country.Code
+		prices, ok := pricesByCountry[countryCode]
+		if !ok {
+			continue
+		}
+		checked = true
+
+		price := prices[int(game.GameID)]
+		if price == nil {
+			// Игра бесплатная или недоступна в регионе
+			continue
+		}
+
+		snapshot := &entities.PriceSnapshot{
+			GameID:      game.GameID,
+			CountryCode: countryCode,
+			Price:       price.Final,
+			Currency:    price.Currency,
+			Discount:    price.Discount(),
+			CheckedAt:   now,
+		}
+
+		previous, err := s.snapshots.GetLatestSnapshot(ctx, game.GameID, countryCode)
+		if err != nil {
+			return err
+		}
+		if err := s.snapshots.SaveSnapshot(ctx, snapshot); err != nil {
+			return err
+		}
+
+		if isPriceDrop(previous, snapshot) {
+			drop.Changes = append(drop.Changes, entities.PriceChange{
+				CountryCode: countryCode,
+				CountryFlag: country.Flag,
+				Currency:    snapshot.Currency,
+				OldPrice:    previous.Price,
+				NewPrice:    snapshot.Price,
+				OldDiscount: previous.Discount,
+				NewDiscount: snapshot.Discount,
+			})
+		}
+	}
+
+	// Если Steam не ответил ни в одном регионе, игра остается в очереди
+	// и проверяется на следующем запуске, а не через recheckAfter
+	if !checked {
+		return fmt.Errorf("не удалось получить цены ни в одном регионе")
+	}
+
+	if err := s.games.MarkGameChecked(ctx, game.GameID, now); err != nil {
+		return err
+	}
+
+	if len(drop.Changes) == 0 {
+		return nil
+	}
+
+	return s.notifySubscribers(ctx, drop)
+}
+
+// notifySubscribers отправляет уведомление о снижении цены всем, кто отслеживает игру
+func (s *PriceWatcherService) notifySubscribers(ctx context.Context, drop *entities.PriceDrop) error {
+	chatIDs, err := s.games.GetSubscribers(ctx, drop.GameID)
+	if err != nil {
+		return fmt.Errorf("не удалось уведомить подписчиков: %w", err)
+	}
+
+	s.logger.Info(ctx, "Цена снизилась", "game", drop.GameName, "regions", len(drop.Changes), "subscribers", len(chatIDs))
+	for _, chatID := range chatIDs {
+		// Пользователь мог заблокировать бота — это не повод не уведомлять остальных
+		if err := s.notifier.NotifyPriceDrop(ctx, chatID, drop); err != nil {
+			s.logger.Error(ctx, "Ошибка отправки уведомления", err, "chatID", chatID)
+		}
+	}
+
+	return nil
+}
+
+// isPriceDrop проверяет, снизилась ли финальная цена или началась скидка.
+// Цены в разных валютах не сравниваются (Steam иногда меняет валюту региона).
+func isPriceDrop(previous, current *entities.PriceSnapshot) bool {
+	if previous == nil || previous.Currency != current.Currency {
+		return false
+	}
+
+	discountStarted := previous.Discount == 0 && current.Discount > 0
+	return current.Price < previous.Price || discountStarted
+}
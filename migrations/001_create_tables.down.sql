@@ -0,0 +1,5 @@
+-- Откат миграции 001: удаление таблиц отслеживания цен
+-- Индексы удаляются вместе с таблицами
+
+DROP TABLE IF EXISTS price_snapshots;
+DROP TABLE IF EXISTS tracked_games;
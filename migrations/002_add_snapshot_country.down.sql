@@ -0,0 +1,4 @@
+-- Откат миграции 002: снимки цен снова без региона
+
+DROP INDEX IF EXISTS idx_price_snapshots_game_country;
+ALTER TABLE price_snapshots DROP COLUMN IF EXISTS country_code;
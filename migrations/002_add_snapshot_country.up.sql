@@ -0,0 +1,9 @@
+-- Миграция 002: Регион в истории цен
+-- Цены в Steam зависят от региона магазина, поэтому снимок цены
+-- должен знать, для какой страны он сделан
+
+-- country_code - код страны магазина Steam (RU, KZ, TR, PL)
+ALTER TABLE price_snapshots ADD COLUMN IF NOT EXISTS country_code VARCHAR(2) NOT NULL DEFAULT '';
+
+-- Индекс для быстрого получения последней цены игры в конкретном регионе
+CREATE INDEX IF NOT EXISTS idx_price_snapshots_game_country ON price_snapshots(game_id, country_code, checked_at DESC);
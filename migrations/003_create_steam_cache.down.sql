@@ -0,0 +1,3 @@
+-- Откат миграции 003: удаление постоянного кэша ответов Steam
+
+DROP TABLE IF EXISTS steam_cache;
@@ -0,0 +1,16 @@
+-- Миграция 003: Постоянный кэш ответов Steam
+-- Позволяет не запрашивать заново популярные игры после перезапуска бота
+
+CREATE TABLE IF NOT EXISTS steam_cache (
+    -- key - ключ кэша (метод и аргументы запроса)
+    key TEXT PRIMARY KEY,
+
+    -- value - ответ Steam в формате JSON
+    value BYTEA NOT NULL,
+
+    -- expires_at - до какого момента значение считается актуальным
+    expires_at TIMESTAMP NOT NULL
+);
+
+-- Индекс по expires_at - для быстрой очистки устаревших значений
+CREATE INDEX IF NOT EXISTS idx_steam_cache_expires_at ON steam_cache(expires_at);
@@ -0,0 +1,3 @@
+-- Откат миграции 004: удаление настроек пользователей
+
+DROP TABLE IF EXISTS user_settings;
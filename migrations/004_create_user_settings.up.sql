@@ -0,0 +1,19 @@
+-- Миграция 004: Настройки пользователей
+-- Каждый чат может выбрать свои регионы, валюту для конвертации и язык
+
+CREATE TABLE IF NOT EXISTS user_settings (
+    -- chat_id - ID чата в Telegram (настройки хранятся на чат, а не на пользователя)
+    chat_id BIGINT PRIMARY KEY,
+
+    -- regions - коды выбранных регионов (RU, KZ, TR, PL)
+    regions TEXT[] NOT NULL,
+
+    -- home_currency - валюта, в которую конвертируются цены (RUB, USD, EUR...)
+    home_currency VARCHAR(3) NOT NULL,
+
+    -- language - язык сообщений бота (ru, en)
+    language VARCHAR(8) NOT NULL,
+
+    -- updated_at - когда настройки последний раз менялись
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
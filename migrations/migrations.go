@@ -0,0 +1,12 @@
+// Package migrations содержит SQL миграции базы данных, встроенные в бинарник.
+//
+// Имена файлов: <версия>_<название>.up.sql и <версия>_<название>.down.sql.
+// Примененную миграцию нельзя менять — для изменения схемы добавьте новую.
+package migrations
+
+import "embed"
+
+// FS — файлы миграций
+//
+//go:embed *.sql
+var FS embed.FS